@@ -0,0 +1,175 @@
+package anomaly
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestRing(t *testing.T) {
+	r := NewRing(3)
+	assert.Equal(t, 0, r.Len())
+	assert.Equal(t, .0, r.Mean())
+
+	for _, v := range []float64{1, 2, 3} {
+		_, full := r.Push(v)
+		assert.False(t, full)
+	}
+	assert.True(t, r.Full())
+	assert.Equal(t, 6.0, r.Sum())
+
+	old, full := r.Push(4)
+	assert.True(t, full)
+	assert.Equal(t, 1.0, old)
+	assert.Equal(t, 3, r.Len())
+	assert.Equal(t, 9.0, r.Sum())
+	assert.Equal(t, 3.0, r.Mean())
+	assert.Equal(t, 2.0, r.At(0))
+	assert.Equal(t, 4.0, r.At(2))
+	assert.Equal(t, 4.0, r.Last(0))
+	assert.Equal(t, 3.0, r.Last(1))
+
+	assert.Panics(t, func() {
+		r.At(3)
+	})
+	assert.Panics(t, func() {
+		NewRing(0)
+	})
+}
+
+// observe returns scores of values observed at times 0, 1, 2, ...
+func observe(d Detector, values ...float64) []Score {
+	result := make([]Score, len(values))
+	for i, v := range values {
+		result[i] = d.Observe(float64(i), v)
+	}
+
+	return result
+}
+
+// ready returns values of ready scores
+func ready(scores []Score) []float64 {
+	var result []float64
+	for _, s := range scores {
+		if s.Ready {
+			result = append(result, s.Value)
+		}
+	}
+
+	return result
+}
+
+func TestStages(t *testing.T) {
+	values := []float64{1, 3, 2, 6, 4}
+
+	useCases := map[string]struct {
+		detector Detector
+		expected []float64
+	}{
+		"sum": {
+			detector: Sum(2),
+			expected: []float64{4, 5, 8, 10},
+		},
+		"mean": {
+			detector: Mean(3),
+			expected: []float64{2, 11.0 / 3, 4},
+		},
+		"diff": {
+			detector: Diff(1),
+			expected: []float64{2, -1, 4, -2},
+		},
+		"diff with lag": {
+			detector: Diff(2),
+			expected: []float64{1, 3, 2},
+		},
+		"absolute second difference": {
+			detector: Pipeline(Diff(1), Diff(1), Abs()),
+			expected: []float64{3, 5, 6},
+		},
+		"squash": {
+			detector: Squash(),
+			expected: []float64{0.5, 0.75, 2.0 / 3, 6.0 / 7, 0.8},
+		},
+		"squashed mean": {
+			detector: SquashedMean(5),
+			expected: []float64{(0.5 + 0.75 + 2.0/3 + 6.0/7 + 0.8) / 5},
+		},
+	}
+	for name, uc := range useCases {
+		t.Run(name, func(t *testing.T) {
+			actual := ready(observe(uc.detector, values...))
+			assert.Len(t, actual, len(uc.expected))
+			for i := range uc.expected {
+				assert.InDelta(t, uc.expected[i], actual[i], 1e-12)
+			}
+		})
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	d := Pipeline(Diff(1), Mean(2))
+	scores := observe(d, 1, 2, 4, 8)
+
+	// time is passed through stages
+	assert.Equal(t, Score{T: 0}, scores[0])
+	assert.Equal(t, Score{T: 1}, scores[1])
+	assert.Equal(t, Score{T: 2, Value: 1.5, Ready: true}, scores[2])
+	assert.Equal(t, Score{T: 3, Value: 3, Ready: true}, scores[3])
+
+	// pipeline without stages passes values through
+	assert.Equal(t, Score{T: 1, Value: 2, Ready: true}, Pipeline().Observe(1, 2))
+}
+
+// Window of ring buffer is updated in O(1), and long stream doesn't accumulate error of running sum
+func TestMean_longStream(t *testing.T) {
+	d := Mean(10)
+	var s Score
+	for i := 0; i < 1000000; i++ {
+		s = d.Observe(float64(i), math.Sin(float64(i)))
+	}
+
+	expected := .0
+	for i := 1000000 - 10; i < 1000000; i++ {
+		expected += math.Sin(float64(i))
+	}
+	assert.InDelta(t, expected/10, s.Value, 1e-9)
+}
+
+// TestRing_largeLevel checks that variance doesn't lose precision when values are far from zero,
+// like latencies in nanoseconds, where sum of squares would cancel out.
+func TestRing_largeLevel(t *testing.T) {
+	rnd := rand.New(rand.NewSource(0))
+	r, small := NewRing(20), NewRing(20)
+	for i := 0; i < 100000; i++ {
+		v := rnd.NormFloat64()
+		r.Push(1e8 + v)
+		small.Push(v)
+	}
+
+	assert.InDelta(t, small.Variance(), r.Variance(), 1e-6)
+	assert.InDelta(t, small.Mean()+1e8, r.Mean(), 1e-6)
+
+	r = NewRing(1)
+	r.Push(1e8)
+	r.Push(2e8)
+	assert.Equal(t, 2e8, r.Mean())
+	assert.Equal(t, .0, r.Variance())
+}
+
+func TestRing_Constant(t *testing.T) {
+	// updates after a jump leave rounding errors, but variance of constant values is exactly zero
+	r := NewRing(3)
+	for _, v := range []float64{1, 2, 1, 2, 1e8, 1e8} {
+		r.Push(v)
+	}
+	assert.False(t, r.Constant())
+	r.Push(1e8)
+	assert.True(t, r.Constant())
+	assert.Equal(t, .0, r.Variance())
+	assert.Equal(t, 1e8, r.Mean())
+
+	r.Push(1e8 + 1)
+	assert.False(t, r.Constant())
+	assert.True(t, r.Variance() > 0)
+}
@@ -0,0 +1,55 @@
+// Package anomaly detects anomalies in streams of observations, like number of requests per second.
+//
+// Detector observes values one by one, as they arrive, and scores how anomalous they are.
+// Detectors keep only a window of recent observations in ring buffers, so each observation is processed in O(1),
+// no matter how long the stream is. Simple detectors, called stages, are composed into Pipeline,
+// where score of one stage is the value observed by the next.
+//
+// Detectors hold state of the stream, so they are not safe for concurrent use.
+package anomaly
+
+// Score is how anomalous is observation at time T.
+type Score struct {
+	T     float64
+	Value float64
+	// Ready is false until detector observed enough values to compute score, like full window
+	Ready bool
+	// Alert is true when score crossed threshold of detector
+	Alert bool
+}
+
+// Detector scores observations of a stream, in order of their time.
+type Detector interface {
+	Observe(t, v float64) Score
+}
+
+// DetectorFunc is an adapter that allows use of ordinary function as a stateless Detector.
+type DetectorFunc func(t, v float64) Score
+
+func (f DetectorFunc) Observe(t, v float64) Score {
+	return f(t, v)
+}
+
+// Pipeline passes observation through stages, value of score of one stage is observed by the next one.
+// Next stage observes only ready scores, so score of pipeline is ready when all stages are ready.
+// Score of pipeline is score of its last stage, so only the last stage should raise alerts.
+func Pipeline(stages ...Detector) Detector {
+	return DetectorFunc(func(t, v float64) Score {
+		s := Score{T: t, Value: v, Ready: true}
+		for _, stage := range stages {
+			s = stage.Observe(t, s.Value)
+			if !s.Ready {
+				return s
+			}
+		}
+
+		return s
+	})
+}
+
+// Map returns stateless stage, that transforms each value with function.
+func Map(fn func(v float64) float64) Detector {
+	return DetectorFunc(func(t, v float64) Score {
+		return Score{T: t, Value: fn(v), Ready: true}
+	})
+}
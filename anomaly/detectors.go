@@ -0,0 +1,190 @@
+package anomaly
+
+import "math"
+
+// ZScore detects values that are far from recent values.
+// Value is compared with mean and standard deviation of the previous window of values
+//
+//	z = (v - mean) / stddev
+//
+// and alert is raised when |z| exceeds threshold. Value itself joins window only after it's scored,
+// so a spike doesn't inflate standard deviation it's compared with.
+type ZScore struct {
+	Threshold float64
+	ring      *Ring
+}
+
+// NewZScore returns detector comparing value with previous window of values.
+func NewZScore(window int, threshold float64) *ZScore {
+	return &ZScore{Threshold: threshold, ring: NewRing(window)}
+}
+
+func (z *ZScore) Observe(t, v float64) Score {
+	s := Score{T: t, Ready: z.ring.Full()}
+	if s.Ready {
+		s.Value = standardise(v, z.ring.Mean(), z.ring.StdDev())
+		s.Alert = math.Abs(s.Value) > z.Threshold
+	}
+
+	z.ring.Push(v)
+
+	return s
+}
+
+// standardise returns how many standard deviations value is from mean.
+// When values had no variation, any different value is infinitely far.
+func standardise(v, mean, stddev float64) float64 {
+	if stddev == 0 {
+		if v == mean {
+			return 0
+		}
+		return math.Copysign(math.Inf(1), v-mean)
+	}
+
+	return (v - mean) / stddev
+}
+
+// baseline learns mean and standard deviation of normal behaviour from the first values of stream,
+// which control charts compare with.
+type baseline struct {
+	warmup int
+	n      int
+	mean   float64
+	m2     float64
+}
+
+// observe includes value in baseline, and returns true when baseline is already learned and value wasn't included.
+// Mean and variance are computed with Welford's algorithm, which is stable for long warm-up.
+func (b *baseline) observe(v float64) bool {
+	if b.n >= b.warmup {
+		return true
+	}
+
+	b.n++
+	delta := v - b.mean
+	b.mean += delta / float64(b.n)
+	b.m2 += delta * (v - b.mean)
+
+	return false
+}
+
+func (b *baseline) stddev() float64 {
+	if b.n < 2 {
+		return 0
+	}
+
+	return math.Sqrt(b.m2 / float64(b.n-1))
+}
+
+// EWMA is exponentially weighted moving average control chart.
+// After warm-up, which learns mean and standard deviation of normal behaviour, it tracks
+//
+//	ewma(t) = λ v(t) + (1 - λ) ewma(t-1)
+//
+// and scores how many of its own standard deviations it's from the mean.
+// Alert is raised when score exceeds L. Small λ detects small persistent shifts, λ = 1 is Shewhart chart of single values.
+type EWMA struct {
+	Lambda, L float64
+	baseline  baseline
+	ewma      float64
+	// weight is (1 - λ)^2t, which makes control limits narrower for the first observations
+	weight float64
+}
+
+// NewEWMA returns control chart that learns normal behaviour from the first warmup values.
+func NewEWMA(lambda, l float64, warmup int) *EWMA {
+	return &EWMA{Lambda: lambda, L: l, baseline: baseline{warmup: warmup}, weight: 1}
+}
+
+func (e *EWMA) Observe(t, v float64) Score {
+	if !e.baseline.observe(v) {
+		e.ewma = e.baseline.mean
+		return Score{T: t}
+	}
+
+	e.ewma = e.Lambda*v + (1-e.Lambda)*e.ewma
+	e.weight *= (1 - e.Lambda) * (1 - e.Lambda)
+
+	stddev := e.baseline.stddev() * math.Sqrt(e.Lambda/(2-e.Lambda)*(1-e.weight))
+	value := standardise(e.ewma, e.baseline.mean, stddev)
+
+	return Score{T: t, Value: value, Ready: true, Alert: math.Abs(value) > e.L}
+}
+
+// CUSUM is two-sided cumulative sum control chart.
+// After warm-up, which learns mean and standard deviation of normal behaviour,
+// it accumulates deviations of standardised values that are bigger than slack K, separately up and down
+//
+//	up(t)   = max(0, up(t-1) + z(t) - K)
+//	down(t) = max(0, down(t-1) - z(t) - K)
+//
+// and raises alert when either sum exceeds H, after which sums start again from zero.
+// Score is the bigger of the sums. Typical K = 0.5 detects shift of one standard deviation.
+type CUSUM struct {
+	K, H     float64
+	baseline baseline
+	up, down float64
+}
+
+// NewCUSUM returns control chart that learns normal behaviour from the first warmup values.
+func NewCUSUM(k, h float64, warmup int) *CUSUM {
+	return &CUSUM{K: k, H: h, baseline: baseline{warmup: warmup}}
+}
+
+func (c *CUSUM) Observe(t, v float64) Score {
+	if !c.baseline.observe(v) {
+		return Score{T: t}
+	}
+
+	z := standardise(v, c.baseline.mean, c.baseline.stddev())
+	c.up = math.Max(0, c.up+z-c.K)
+	c.down = math.Max(0, c.down-z-c.K)
+
+	s := Score{T: t, Value: math.Max(c.up, c.down), Ready: true}
+	if s.Value > c.H {
+		s.Alert = true
+		c.up, c.down = 0, 0
+	}
+
+	return s
+}
+
+// PageHinkley detects change of mean of stream, without learning normal behaviour up-front.
+// It accumulates deviations of values from their running mean, reduced by tolerance Delta,
+// and raises alert when accumulated deviation departs from its minimum (or maximum) by more than Lambda
+//
+//	m(t) = ∑ (v(i) - mean(i) - Δ),  PH(t) = m(t) - min m(i)
+//
+// After alert running mean starts again, so detector adapts to new level.
+// Score is the bigger of upward and downward statistics.
+type PageHinkley struct {
+	Delta, Lambda float64
+
+	n             int
+	mean          float64
+	up, minUp     float64
+	down, minDown float64
+}
+
+// NewPageHinkley returns detector of change of mean.
+func NewPageHinkley(delta, lambda float64) *PageHinkley {
+	return &PageHinkley{Delta: delta, Lambda: lambda}
+}
+
+func (p *PageHinkley) Observe(t, v float64) Score {
+	p.n++
+	p.mean += (v - p.mean) / float64(p.n)
+
+	p.up += v - p.mean - p.Delta
+	p.minUp = math.Min(p.minUp, p.up)
+	p.down += p.mean - v - p.Delta
+	p.minDown = math.Min(p.minDown, p.down)
+
+	s := Score{T: t, Value: math.Max(p.up-p.minUp, p.down-p.minDown), Ready: true}
+	if s.Value > p.Lambda {
+		s.Alert = true
+		*p = PageHinkley{Delta: p.Delta, Lambda: p.Lambda}
+	}
+
+	return s
+}
@@ -0,0 +1,161 @@
+package anomaly
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+const (
+	// period of sine signal
+	period  = 50
+	spikeAt = 300
+	shiftAt = 600
+)
+
+// sineWithNoise returns sine signal with gaussian noise, single spike at spikeAt, and shift of level from shiftAt.
+func sineWithNoise(seed int64) []float64 {
+	rnd := rand.New(rand.NewSource(seed))
+
+	result := make([]float64, 1000)
+	for i := range result {
+		result[i] = math.Sin(2*math.Pi*float64(i)/period) + rnd.NormFloat64()*0.1
+		if i == spikeAt {
+			result[i] += 2
+		}
+		if i >= shiftAt {
+			result[i] += 1
+		}
+	}
+
+	return result
+}
+
+// alerts returns times of alerts raised by detector
+func alerts(d Detector, values []float64) []int {
+	var result []int
+	for i, v := range values {
+		if d.Observe(float64(i), v).Alert {
+			result = append(result, i)
+		}
+	}
+
+	return result
+}
+
+func TestDetectors(t *testing.T) {
+	useCases := map[string]struct {
+		detector Detector
+		// spike is true when detector should detect spike, not only change of level
+		spike bool
+	}{
+		"z-score": {
+			detector: NewZScore(period, 5),
+			spike:    true,
+		},
+		"ewma": {
+			detector: NewEWMA(0.2, 4, period),
+			spike:    true,
+		},
+		"cusum": {
+			detector: NewCUSUM(0.5, 10, period),
+			spike:    true,
+		},
+		"page-hinkley": {
+			detector: NewPageHinkley(0.05, 3),
+		},
+	}
+	for name, uc := range useCases {
+		t.Run(name, func(t *testing.T) {
+			// Differencing with period removes seasonality, and leaves noise,
+			// that's why detectors see spike at spikeAt and spikeAt+period,
+			// and shift of level between shiftAt and shiftAt+period.
+			d := Pipeline(Diff(period), uc.detector)
+			result := alerts(d, sineWithNoise(0))
+			t.Logf("alerts: %v", result)
+
+			detected := func(from, to int) bool {
+				for _, a := range result {
+					if a >= from && a < to {
+						return true
+					}
+				}
+				return false
+			}
+
+			for _, a := range result {
+				expected := (a >= spikeAt && a < spikeAt+period+10) || (a >= shiftAt && a < shiftAt+period+10)
+				assert.True(t, expected, "false alarm at %d", a)
+			}
+
+			assert.True(t, detected(shiftAt, shiftAt+5), "shift of level not detected")
+			if uc.spike {
+				assert.True(t, detected(spikeAt, spikeAt+1), "spike not detected")
+			}
+		})
+	}
+}
+
+// Seasonal signal has to be differenced, without it control chart raises alerts every half of period.
+func TestEWMA_seasonality(t *testing.T) {
+	result := alerts(NewEWMA(0.2, 3, 2*period), sineWithNoise(0)[:spikeAt])
+	assert.NotEmpty(t, result)
+}
+
+func TestZScore(t *testing.T) {
+	z := NewZScore(3, 2)
+	scores := observe(z, 1, 2, 3, 2, 10)
+
+	assert.False(t, scores[2].Ready)
+	// 2 compared with 1, 2, 3
+	assert.Equal(t, Score{T: 3, Value: 0, Ready: true}, scores[3])
+	// 10 compared with 2, 3, 2
+	assert.True(t, scores[4].Alert)
+	assert.InDelta(t, (10-7.0/3)/math.Sqrt(1.0/3), scores[4].Value, 1e-9)
+
+	// constant values make any change infinitely anomalous
+	constant := NewZScore(2, 3)
+	observe(constant, 1, 1)
+	assert.Equal(t, 0.0, constant.Observe(2, 1).Value)
+	assert.Equal(t, math.Inf(1), constant.Observe(3, 2).Value)
+}
+
+// TestZScore_largeLevel checks that noise around large level, like latency in nanoseconds, doesn't raise alerts.
+func TestZScore_largeLevel(t *testing.T) {
+	rnd := rand.New(rand.NewSource(0))
+	values := make([]float64, 1000)
+	for i := range values {
+		values[i] = 1e8 + rnd.NormFloat64()
+	}
+
+	assert.Empty(t, alerts(NewZScore(20, 5), values))
+}
+
+func TestCUSUM_reset(t *testing.T) {
+	c := NewCUSUM(0.5, 2, 4)
+	// baseline: mean 0, standard deviation √(4/3)
+	observe(c, -1, 1, -1, 1)
+
+	assert.InDelta(t, 1.5/math.Sqrt(4.0/3)-0.5, c.Observe(4, 1.5).Value, 1e-9)
+	s := c.Observe(5, 3)
+	assert.True(t, s.Alert)
+	// sums start from zero after alert
+	assert.Equal(t, 0.0, c.Observe(6, 0).Value)
+}
+
+func TestPageHinkley_adapts(t *testing.T) {
+	p := NewPageHinkley(0.1, 5)
+
+	var stream []float64
+	for i := 0; i < 100; i++ {
+		stream = append(stream, 0)
+	}
+	for i := 0; i < 200; i++ {
+		stream = append(stream, 1)
+	}
+
+	result := alerts(p, stream)
+	assert.Len(t, result, 1, "after alert detector adapts to new level")
+	assert.True(t, result[0] >= 100 && result[0] < 110)
+}
@@ -0,0 +1,90 @@
+package anomaly
+
+// Point is value observed at time T.
+type Point struct {
+	T, V float64
+}
+
+// ObserveAll observes points in order, and returns their scores.
+func ObserveAll(d Detector, points []Point) []Score {
+	result := make([]Score, len(points))
+	for i, p := range points {
+		result[i] = d.Observe(p.T, p.V)
+	}
+
+	return result
+}
+
+// Segment is time [From, To] during which anomaly happened, it's a ground truth label.
+type Segment struct {
+	From, To float64
+}
+
+// Contains returns true when t is within the segment, or at most delay after it.
+func (s Segment) Contains(t, delay float64) bool {
+	return t >= s.From && t <= s.To+delay
+}
+
+// Evaluation counts alerts and anomalies that were detected, or not.
+// Alert is true positive when it's raised during anomaly, or at most Delay after it,
+// because detectors need few observations to notice a change. Anomaly is detected
+// when it has at least one true positive alert.
+type Evaluation struct {
+	TruePositives  int
+	FalsePositives int
+	Detected       int
+	Missed         int
+}
+
+// Evaluate compares alerts of scores with ground truth anomalies.
+func Evaluate(scores []Score, anomalies []Segment, delay float64) Evaluation {
+	var result Evaluation
+	detected := make([]bool, len(anomalies))
+	for _, s := range scores {
+		if !s.Alert {
+			continue
+		}
+
+		positive := false
+		for i, a := range anomalies {
+			if a.Contains(s.T, delay) {
+				positive = true
+				detected[i] = true
+			}
+		}
+
+		if positive {
+			result.TruePositives++
+		} else {
+			result.FalsePositives++
+		}
+	}
+
+	for _, d := range detected {
+		if d {
+			result.Detected++
+		} else {
+			result.Missed++
+		}
+	}
+
+	return result
+}
+
+// Precision returns fraction of alerts that were raised during anomalies.
+func (e Evaluation) Precision() float64 {
+	return ratio(e.TruePositives, e.TruePositives+e.FalsePositives)
+}
+
+// Recall returns fraction of anomalies that were detected.
+func (e Evaluation) Recall() float64 {
+	return ratio(e.Detected, e.Detected+e.Missed)
+}
+
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+
+	return float64(a) / float64(b)
+}
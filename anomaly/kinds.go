@@ -0,0 +1,234 @@
+package anomaly
+
+import (
+	"math"
+	"sort"
+)
+
+// Spike detects single values that jump far above or below recent values [A1].
+// Unlike ZScore it compares value with median and median absolute deviation (MAD) of previous window,
+// which are not distorted by earlier spikes in the window
+//
+//	score = (v - median) / (1.4826 * MAD)
+//
+// where 1.4826 makes MAD of normal distribution equal to its standard deviation.
+// Median is found by sorting window, so observation costs O(window log window).
+type Spike struct {
+	Threshold float64
+	ring      *Ring
+	sorted    []float64
+}
+
+// NewSpike returns detector comparing value with median of previous window of values.
+func NewSpike(window int, threshold float64) *Spike {
+	return &Spike{Threshold: threshold, ring: NewRing(window), sorted: make([]float64, window)}
+}
+
+func (s *Spike) Observe(t, v float64) Score {
+	score := Score{T: t, Ready: s.ring.Full()}
+	if score.Ready {
+		for i := range s.sorted {
+			s.sorted[i] = s.ring.At(i)
+		}
+		median := medianOf(s.sorted)
+		for i := range s.sorted {
+			s.sorted[i] = math.Abs(s.sorted[i] - median)
+		}
+		mad := medianOf(s.sorted)
+
+		score.Value = standardise(v, median, 1.4826*mad)
+		score.Alert = math.Abs(score.Value) > s.Threshold
+	}
+
+	s.ring.Push(v)
+
+	return score
+}
+
+// medianOf sorts values in place, and returns their median.
+func medianOf(values []float64) float64 {
+	sort.Float64s(values)
+	n := len(values)
+	if n%2 == 1 {
+		return values[n/2]
+	}
+
+	return (values[n/2-1] + values[n/2]) / 2
+}
+
+// Periodicity detects when stream that should repeat with period stops repeating [A2].
+// It tracks correlation between values and values one period earlier, over sliding window
+//
+//	r = corr(v(t), v(t-period))
+//
+// which is close to 1 for regular stream, and drops towards 0 when regularity is lost.
+// Score is 1 - r, and alert is raised when correlation drops below MinCorrelation.
+// Means and co-moment of pairs are updated with Welford's algorithm, so observation costs O(1),
+// and correlation stays precise for stream far from zero.
+type Periodicity struct {
+	MinCorrelation float64
+	// previous holds the last period of values
+	previous *Ring
+	// x holds values, and y values one period earlier, of the last window of pairs
+	x, y *Ring
+	// pairs holds co-moment of pairs in window
+	pairs moments
+	// pushed counts pairs since co-moment was computed from values
+	pushed int
+}
+
+// NewPeriodicity returns detector of lost regularity, with correlation computed over window of values.
+func NewPeriodicity(period, window int, minCorrelation float64) *Periodicity {
+	return &Periodicity{
+		MinCorrelation: minCorrelation,
+		previous:       NewRing(period),
+		x:              NewRing(window),
+		y:              NewRing(window),
+	}
+}
+
+func (p *Periodicity) Observe(t, v float64) Score {
+	if !p.previous.Full() {
+		p.previous.Push(v)
+		return Score{T: t}
+	}
+
+	earlier, _ := p.previous.Push(v)
+	oldX, full := p.x.Push(v)
+	oldY, _ := p.y.Push(earlier)
+	if full {
+		p.pairs.remove(oldX, oldY)
+	}
+	p.pairs.add(v, earlier)
+
+	// like in Ring, rounding errors of updates are removed once per window
+	if p.pushed++; p.pushed == p.x.Cap() {
+		p.pushed = 0
+		p.pairs = moments{}
+		for i := 0; i < p.x.Len(); i++ {
+			p.pairs.add(p.x.At(i), p.y.At(i))
+		}
+	}
+
+	s := Score{T: t, Ready: p.x.Full()}
+	if s.Ready {
+		r := p.correlation()
+		s.Value = 1 - r
+		s.Alert = r < p.MinCorrelation
+	}
+
+	return s
+}
+
+// correlation returns Pearson correlation of pairs in window.
+// Constant stream is perfectly regular, but stream that became constant, or stopped being constant, is not.
+func (p *Periodicity) correlation() float64 {
+	switch {
+	case p.x.Constant() && p.y.Constant():
+		return 1
+	case p.x.Constant() || p.y.Constant():
+		return 0
+	}
+
+	return p.pairs.c / float64(p.x.Len()-1) / math.Sqrt(p.x.Variance()*p.y.Variance())
+}
+
+// Jitter detects change of variability of stream [A3].
+// It compares variance of short recent window with variance of long window of values that preceded it
+//
+//	score = variance(recent) / variance(reference)
+//
+// and raises alert when variance grows more than Ratio times. Variance of short window is noisy estimate,
+// that's why Ratio should be bigger for shorter windows. Values observed during alert don't become reference,
+// so persistent growth of variance keeps raising alerts.
+// To detect jitter around trend or seasonality, observe second differences of values,
+// like Pipeline(Diff(1), Diff(1), NewJitter(...)), which are close to zero for smooth stream.
+type Jitter struct {
+	Ratio             float64
+	recent, reference *Ring
+}
+
+// NewJitter returns detector comparing variance of recent values with variance of reference values before them.
+func NewJitter(recent, reference int, ratio float64) *Jitter {
+	return &Jitter{Ratio: ratio, recent: NewRing(recent), reference: NewRing(reference)}
+}
+
+func (j *Jitter) Observe(t, v float64) Score {
+	old, full := j.recent.Push(v)
+
+	s := Score{T: t, Ready: j.reference.Full()}
+	if s.Ready {
+		// like in standardise, any jitter of constant stream is infinitely anomalous
+		switch {
+		case j.reference.Constant() && j.recent.Constant():
+			s.Value = 0
+		case j.reference.Constant():
+			s.Value = math.Inf(1)
+		default:
+			s.Value = j.recent.Variance() / j.reference.Variance()
+		}
+		s.Alert = s.Value > j.Ratio
+	}
+
+	// during alert reference is not updated,
+	// otherwise jitter would hide the next one
+	if full && !s.Alert {
+		j.reference.Push(old)
+	}
+
+	return s
+}
+
+// Gap detects missing data [A4], when time between observations is much longer than usual.
+// Usual interval is learned as exponentially weighted average of intervals observed so far,
+// unless Interval is set before the first observation. Score is time since previous observation
+// divided by usual interval, and alert is raised when it exceeds Tolerance.
+//
+// Observation that arrives after gap reveals it, but when data stops arriving there is nothing to observe,
+// that's why Check scores time elapsed since the last observation, and should be called periodically.
+type Gap struct {
+	// Interval is expected time between observations, zero means it's learned from stream
+	Interval  float64
+	Tolerance float64
+	learning  bool
+	last      float64
+	observed  int
+}
+
+// gapLearningRate is weight of the newest interval in learned average interval.
+const gapLearningRate = 0.1
+
+// NewGap returns detector of missing data, which learns usual interval between observations.
+func NewGap(tolerance float64) *Gap {
+	return &Gap{Tolerance: tolerance}
+}
+
+// Observe scores interval between previous observation and observation at time t, value is not used.
+// Intervals that raised alert are not learned, so a gap doesn't make usual interval longer.
+func (g *Gap) Observe(t, v float64) Score {
+	s := g.Check(t)
+	switch {
+	case g.observed == 0:
+		g.learning = g.Interval == 0
+	case g.observed == 1 && g.learning:
+		g.Interval = t - g.last
+	case g.learning && !s.Alert:
+		g.Interval += gapLearningRate * (t - g.last - g.Interval)
+	}
+
+	g.last = t
+	g.observed++
+
+	return s
+}
+
+// Check scores time elapsed since the last observation until now, without observing anything.
+func (g *Gap) Check(now float64) Score {
+	s := Score{T: now, Ready: g.observed >= 2 && g.Interval > 0}
+	if s.Ready {
+		s.Value = (now - g.last) / g.Interval
+		s.Alert = s.Value > g.Tolerance
+	}
+
+	return s
+}
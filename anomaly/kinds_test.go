@@ -0,0 +1,203 @@
+package anomaly
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// spikes returns noisy level with single value spikes, and times of spikes as ground truth.
+func spikes(rnd *rand.Rand) ([]Point, []Segment) {
+	var points []Point
+	var anomalies []Segment
+	for i := 0; i < 1000; i++ {
+		t := float64(i)
+		v := 10 + rnd.NormFloat64()
+		if i >= 100 && i%100 == 37 {
+			v += 10 * math.Copysign(1, rnd.Float64()-0.5)
+			anomalies = append(anomalies, Segment{From: t, To: t})
+		}
+		points = append(points, Point{T: t, V: v})
+	}
+
+	return points, anomalies
+}
+
+// irregular returns sine signal with a given period, that is replaced by noise of similar magnitude during anomalies.
+func irregular(rnd *rand.Rand, period int) ([]Point, []Segment) {
+	anomalies := []Segment{{From: 400, To: 499}, {From: 750, To: 799}}
+
+	var points []Point
+	for i := 0; i < 1000; i++ {
+		t := float64(i)
+		v := math.Sin(2*math.Pi*t/float64(period)) + rnd.NormFloat64()*0.1
+		for _, a := range anomalies {
+			if a.Contains(t, 0) {
+				v = rnd.NormFloat64() * 0.7
+			}
+		}
+		points = append(points, Point{T: t, V: v})
+	}
+
+	return points, anomalies
+}
+
+// jittery returns sine signal of given amplitude with small noise, that becomes ten times bigger during anomalies.
+func jittery(rnd *rand.Rand, amplitude float64) ([]Point, []Segment) {
+	anomalies := []Segment{{From: 300, To: 349}, {From: 700, To: 719}}
+
+	var points []Point
+	for i := 0; i < 1000; i++ {
+		t := float64(i)
+		noise := 0.05
+		for _, a := range anomalies {
+			if a.Contains(t, 0) {
+				noise = 0.5
+			}
+		}
+		points = append(points, Point{T: t, V: amplitude*math.Sin(2*math.Pi*t/50) + rnd.NormFloat64()*noise})
+	}
+
+	return points, anomalies
+}
+
+// gaps returns observations arriving roughly every second, with few periods without any observation.
+func gaps(rnd *rand.Rand) ([]Point, []Segment) {
+	anomalies := []Segment{{From: 200, To: 210}, {From: 500, To: 530}, {From: 800, To: 805}}
+
+	var points []Point
+	for t := .0; t < 1000; t += 1 + (rnd.Float64()-0.5)*0.4 {
+		missing := false
+		for _, a := range anomalies {
+			if a.Contains(t, 0) {
+				missing = true
+			}
+		}
+		if !missing {
+			points = append(points, Point{T: t, V: rnd.Float64()})
+		}
+	}
+
+	return points, anomalies
+}
+
+func TestKinds(t *testing.T) {
+	useCases := map[string]struct {
+		generate func(rnd *rand.Rand) ([]Point, []Segment)
+		detector func() Detector
+		// delay is how long after anomaly alert is still true positive
+		delay float64
+	}{
+		"spike [A1]": {
+			generate: spikes,
+			detector: func() Detector { return NewSpike(30, 5) },
+		},
+		"periodicity [A2]": {
+			generate: func(rnd *rand.Rand) ([]Point, []Segment) { return irregular(rnd, 20) },
+			detector: func() Detector { return NewPeriodicity(20, 40, 0.5) },
+			delay:    40,
+		},
+		"jitter [A3]": {
+			// second differences of smooth sine are close to zero, what is left is noise
+			generate: func(rnd *rand.Rand) ([]Point, []Segment) { return jittery(rnd, 1) },
+			detector: func() Detector { return Pipeline(Diff(1), Diff(1), NewJitter(10, 100, 5)) },
+			delay:    10,
+		},
+		"jitter of level [A3]": {
+			generate: func(rnd *rand.Rand) ([]Point, []Segment) { return jittery(rnd, 0) },
+			detector: func() Detector { return NewJitter(10, 100, 5) },
+			delay:    10,
+		},
+		"gap [A4]": {
+			generate: gaps,
+			detector: func() Detector { return NewGap(3) },
+			delay:    2,
+		},
+	}
+	for name, uc := range useCases {
+		t.Run(name, func(t *testing.T) {
+			// offset moves stream far from zero, like latency in nanoseconds, which must not change alerts
+			for _, offset := range []float64{0, 1e8} {
+				for seed := int64(0); seed < 5; seed++ {
+					points, anomalies := uc.generate(rand.New(rand.NewSource(seed)))
+					for i := range points {
+						points[i].V += offset
+					}
+
+					e := Evaluate(ObserveAll(uc.detector(), points), anomalies, uc.delay)
+					t.Logf("offset=%g seed=%d %+v precision=%.2f recall=%.2f", offset, seed, e, e.Precision(), e.Recall())
+
+					assert.Equal(t, 1.0, e.Recall(), "offset=%g seed=%d", offset, seed)
+					assert.True(t, e.Precision() >= 0.9, "offset=%g seed=%d precision=%v", offset, seed, e.Precision())
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	scores := []Score{
+		{T: 1, Alert: true},
+		{T: 5, Alert: true},
+		{T: 6, Alert: false},
+		{T: 7, Alert: true},
+		{T: 20, Alert: true},
+	}
+	anomalies := []Segment{{From: 4, To: 5}, {From: 10, To: 12}}
+
+	e := Evaluate(scores, anomalies, 2)
+	assert.Equal(t, Evaluation{TruePositives: 2, FalsePositives: 2, Detected: 1, Missed: 1}, e)
+	assert.Equal(t, 0.5, e.Precision())
+	assert.Equal(t, 0.5, e.Recall())
+	assert.Equal(t, .0, Evaluation{}.Precision())
+}
+
+func TestGap_Check(t *testing.T) {
+	g := NewGap(3)
+	assert.False(t, g.Check(10).Ready)
+
+	for _, at := range []float64{0, 1, 2, 3, 4} {
+		assert.False(t, g.Observe(at, 0).Alert)
+	}
+	assert.Equal(t, 1.0, g.Interval)
+
+	// data stopped arriving
+	assert.False(t, g.Check(6).Alert)
+	assert.True(t, g.Check(8).Alert)
+	assert.Equal(t, 4.0, g.Check(8).Value)
+
+	// fixed interval is not learned
+	g = &Gap{Interval: 2, Tolerance: 3}
+	observe(g, 0, 0, 0)
+	assert.Equal(t, 2.0, g.Interval)
+	assert.Equal(t, 0.5, g.Check(3).Value)
+}
+
+func TestPeriodicity_constant(t *testing.T) {
+	scores := observe(NewPeriodicity(2, 3, 0.5), 1, 1, 1, 1, 1, 1)
+	assert.Equal(t, []float64{0, 0}, ready(scores))
+
+	// stream that became constant lost its regularity, until values one period earlier are constant too
+	var alerted []float64
+	scores = observe(NewPeriodicity(2, 3, 0.5), 1, 2, 1, 2, 1, 5, 5, 5, 5, 5)
+	for _, s := range scores {
+		if s.Alert {
+			alerted = append(alerted, s.T)
+		}
+	}
+	assert.Equal(t, []float64{7, 8}, alerted)
+	assert.Equal(t, 1.0, scores[8].Value)
+	assert.Equal(t, .0, scores[9].Value)
+}
+
+func TestJitter_constant(t *testing.T) {
+	// constant stream, like flat metric, has no jitter
+	scores := observe(NewJitter(2, 3, 4), 5, 5, 5, 5, 5, 5, 5)
+	assert.Equal(t, []float64{0, 0}, ready(scores))
+
+	// any jitter of constant stream is infinitely anomalous
+	scores = observe(NewJitter(2, 3, 4), 5, 5, 5, 5, 5, 5, 7)
+	assert.True(t, scores[6].Alert)
+	assert.Equal(t, math.Inf(1), scores[6].Value)
+}
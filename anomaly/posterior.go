@@ -0,0 +1,268 @@
+package anomaly
+
+import (
+	"fmt"
+	"github.com/widmogrod/probability-playground/distributions"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Model is a hypothesis of how values of feature are distributed,
+// it returns logarithm of probability, or probability density, of value x.
+type Model interface {
+	LogLikelihood(x float64) float64
+}
+
+// ModelFunc is an adapter that allows use of ordinary function as a Model.
+type ModelFunc func(x float64) float64
+
+func (f ModelFunc) LogLikelihood(x float64) float64 {
+	return f(x)
+}
+
+// Normal models feature with normal distribution, like mean of values in bucket.
+// It panics when stddev is not positive.
+func Normal(mean, stddev float64) Model {
+	if !(stddev > 0) {
+		panic(fmt.Sprintf("anomaly: normal model stddev %v is not positive", stddev))
+	}
+
+	return ModelFunc(func(x float64) float64 {
+		z := (x - mean) / stddev
+		return -z*z/2 - math.Log(stddev*math.Sqrt(2*math.Pi))
+	})
+}
+
+// Poisson models count with Poisson distribution, like number of requests in bucket.
+// Value is rounded to the nearest count.
+func Poisson(rate float64) Model {
+	p := distributions.Poisson{Lambda: rate}
+	return ModelFunc(func(x float64) float64 {
+		return p.LogPMF(int(math.Round(x)))
+	})
+}
+
+// Uniform models feature that takes any value from [min, max] with the same probability.
+// It's useful model of anomaly, when nothing more is known about it.
+// It panics when max is not greater than min.
+func Uniform(min, max float64) Model {
+	if !(max > min) {
+		panic(fmt.Sprintf("anomaly: uniform model range [%v, %v] is empty", min, max))
+	}
+
+	return ModelFunc(func(x float64) float64 {
+		if x < min || x > max {
+			return math.Inf(-1)
+		}
+
+		return -math.Log(max - min)
+	})
+}
+
+// Bernoulli models feature which is present (non zero) with probability p, like alert of a detector.
+func Bernoulli(p float64) Model {
+	return ModelFunc(func(x float64) float64 {
+		if x != 0 {
+			return math.Log(p)
+		}
+
+		return math.Log(1 - p)
+	})
+}
+
+// Feature is evidence of anomaly, with models of its values in normal and anomalous bucket.
+type Feature struct {
+	Name      string
+	Normal    Model
+	Anomalous Model
+}
+
+// Evidence holds values of features observed in bucket of time, by name of feature.
+type Evidence map[string]float64
+
+// Posterior computes probability of anomaly in bucket of time given evidence, with Bayes theorem
+//
+//	                     P(E|A) * P(A)
+//	P(A|E) = -------------------------------------
+//	          P(E|A) * P(A) + P(E|¬A) * (1 - P(A))
+//
+// Features are assumed to be independent given hypothesis (naive assumption),
+// so P(E|A) is product of likelihoods of each feature. Computation is done on log odds
+//
+//	log(P(A|E) / P(¬A|E)) = log(P(A) / P(¬A)) + ∑ log(P(e|A) / P(e|¬A))
+//
+// where each feature adds its log likelihood ratio, which is how much it speaks for anomaly.
+type Posterior struct {
+	// Prior is rate of anomalies, probability that bucket is anomalous before evidence is seen, P(A)
+	Prior float64
+	// Threshold is probability of anomaly above which Observe raises alert
+	Threshold float64
+	Features  []Feature
+}
+
+// Probability returns probability of anomaly given evidence, P(A|E).
+// Features missing from evidence are skipped.
+func (p Posterior) Probability(e Evidence) float64 {
+	return p.Explain(e).Posterior
+}
+
+// Observe scores evidence of bucket at time t with probability of anomaly.
+func (p Posterior) Observe(t float64, e Evidence) Score {
+	probability := p.Probability(e)
+
+	return Score{T: t, Value: probability, Ready: true, Alert: probability > p.Threshold}
+}
+
+// Contribution is how much value of feature speaks for anomaly.
+type Contribution struct {
+	Feature string
+	Value   float64
+	// LogLikelihoodRatio is log(P(value|A) / P(value|¬A)), positive speaks for anomaly, negative against
+	LogLikelihoodRatio float64
+}
+
+// Explanation shows how prior and each feature contributed to probability of anomaly.
+type Explanation struct {
+	Prior float64
+	// Evidence holds contributions of features, from the one that speaks for anomaly the most
+	Evidence  []Contribution
+	Posterior float64
+}
+
+// Explain returns contributions of prior and each feature to probability of anomaly.
+func (p Posterior) Explain(e Evidence) Explanation {
+	result := Explanation{Prior: p.Prior}
+	for _, f := range p.Features {
+		x, ok := e[f.Name]
+		if !ok {
+			continue
+		}
+
+		result.Evidence = append(result.Evidence, Contribution{
+			Feature:            f.Name,
+			Value:              x,
+			LogLikelihoodRatio: logRatio(f.Anomalous.LogLikelihood(x), f.Normal.LogLikelihood(x)),
+		})
+	}
+
+	sort.SliceStable(result.Evidence, func(i, j int) bool {
+		return result.Evidence[i].LogLikelihoodRatio > result.Evidence[j].LogLikelihoodRatio
+	})
+
+	result.Posterior = logistic(result.LogOdds())
+
+	return result
+}
+
+// logRatio returns difference of logarithms,
+// value impossible under both hypotheses is no evidence for any of them.
+func logRatio(a, b float64) float64 {
+	if math.IsInf(a, -1) && math.IsInf(b, -1) {
+		return 0
+	}
+
+	return a - b
+}
+
+// PriorLogOdds returns log odds of anomaly before evidence is seen.
+func (e Explanation) PriorLogOdds() float64 {
+	return math.Log(e.Prior) - math.Log(1-e.Prior)
+}
+
+// LogOdds returns log odds of anomaly given evidence, it's sum of prior log odds and contributions of features.
+// Certain evidence, value impossible under one of hypotheses, overrides the prior,
+// and contradicting certain evidence gives NaN.
+func (e Explanation) LogOdds() float64 {
+	evidence := .0
+	for _, c := range e.Evidence {
+		evidence += c.LogLikelihoodRatio
+	}
+	if math.IsInf(evidence, 0) {
+		return evidence
+	}
+
+	return e.PriorLogOdds() + evidence
+}
+
+// logistic turns log odds into probability.
+func logistic(logOdds float64) float64 {
+	return 1 / (1 + math.Exp(-logOdds))
+}
+
+func (e Explanation) WriteText(w io.Writer) error {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "P(anomaly|evidence)=%.4f\n", e.Posterior)
+	fmt.Fprintf(b, "log odds of anomaly:\n")
+	fmt.Fprintf(b, "  %+8.3f  prior P(anomaly)=%.4f\n", e.PriorLogOdds(), e.Prior)
+	for _, c := range e.Evidence {
+		fmt.Fprintf(b, "  %+8.3f  %s=%.4g\n", c.LogLikelihoodRatio, c.Feature, c.Value)
+	}
+	fmt.Fprintf(b, "= %+8.3f  posterior\n", e.LogOdds())
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (e Explanation) String() string {
+	b := &strings.Builder{}
+	_ = e.WriteText(b)
+
+	return b.String()
+}
+
+// Bucket holds values observed in time [T, T+width).
+type Bucket struct {
+	T      float64
+	Values []float64
+}
+
+// Count returns number of values in bucket.
+func (b Bucket) Count() float64 {
+	return float64(len(b.Values))
+}
+
+// Mean returns mean of values in bucket, or zero when bucket is empty.
+func (b Bucket) Mean() float64 {
+	if len(b.Values) == 0 {
+		return 0
+	}
+
+	sum := .0
+	for _, v := range b.Values {
+		sum += v
+	}
+
+	return sum / float64(len(b.Values))
+}
+
+// Buckets groups points into consecutive buckets of time of the same width,
+// points don't have to be ordered by time, and values in bucket keep order of points.
+// Buckets without points are kept, because missing data can be evidence of anomaly.
+// It panics when width is not positive.
+func Buckets(points []Point, width float64) []Bucket {
+	if !(width > 0) {
+		panic(fmt.Sprintf("anomaly: bucket width %v is not positive", width))
+	}
+	if len(points) == 0 {
+		return nil
+	}
+
+	sorted := append([]Point{}, points...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].T < sorted[j].T
+	})
+
+	start := math.Floor(sorted[0].T/width) * width
+	var result []Bucket
+	for _, p := range sorted {
+		i := int((p.T - start) / width)
+		for len(result) <= i {
+			result = append(result, Bucket{T: start + float64(len(result))*width})
+		}
+		result[i].Values = append(result[i].Values, p.V)
+	}
+
+	return result
+}
@@ -0,0 +1,198 @@
+package anomaly
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestPosterior(t *testing.T) {
+	p := Posterior{
+		Prior: 0.1,
+		Features: []Feature{
+			{Name: "latency", Normal: Normal(100, 10), Anomalous: Normal(200, 50)},
+			{Name: "alert", Normal: Bernoulli(0.05), Anomalous: Bernoulli(0.8)},
+		},
+	}
+
+	useCases := map[string]struct {
+		evidence Evidence
+		expected float64
+	}{
+		"no evidence is prior": {
+			evidence: Evidence{},
+			expected: 0.1,
+		},
+		"single feature": {
+			evidence: Evidence{"alert": 1},
+			expected: 0.8 * 0.1 / (0.8*0.1 + 0.05*0.9),
+		},
+		"features multiply": {
+			evidence: Evidence{"alert": 0, "latency": 130},
+			expected: bayesTheorem(0.1,
+				0.2*density(130, 200, 50),
+				0.95*density(130, 100, 10)),
+		},
+		"unknown feature is skipped": {
+			evidence: Evidence{"alert": 1, "cpu": 99},
+			expected: 0.8 * 0.1 / (0.8*0.1 + 0.05*0.9),
+		},
+	}
+	for name, uc := range useCases {
+		t.Run(name, func(t *testing.T) {
+			assert.InDelta(t, uc.expected, p.Probability(uc.evidence), 1e-12)
+		})
+	}
+
+	// bigger prior anomaly rate makes the same evidence more convincing
+	evidence := Evidence{"alert": 1, "latency": 125}
+	low := p.Probability(evidence)
+	p.Prior = 0.3
+	assert.True(t, p.Probability(evidence) > low)
+}
+
+func bayesTheorem(prior, anomalous, normal float64) float64 {
+	return anomalous * prior / (anomalous*prior + normal*(1-prior))
+}
+
+func density(x, mean, stddev float64) float64 {
+	z := (x - mean) / stddev
+	return math.Exp(-z*z/2) / (stddev * math.Sqrt(2*math.Pi))
+}
+
+func TestPosterior_certain(t *testing.T) {
+	p := Posterior{
+		Prior: 0.01,
+		Features: []Feature{
+			{Name: "requests", Normal: Poisson(5), Anomalous: Uniform(0, 1000)},
+		},
+	}
+
+	// fraction of request is impossible under both hypotheses, so it's no evidence
+	assert.InDelta(t, 0.01, p.Probability(Evidence{"requests": -3}), 1e-12)
+	// more requests than anomalous model allows is certainly normal
+	assert.Equal(t, .0, p.Probability(Evidence{"requests": 2000}))
+
+	p.Prior = 0
+	assert.Equal(t, .0, p.Probability(Evidence{"requests": 0}))
+}
+
+func TestExplanation(t *testing.T) {
+	p := Posterior{
+		Prior: 0.05,
+		Features: []Feature{
+			{Name: "requests", Normal: Poisson(100), Anomalous: Uniform(0, 1000)},
+			{Name: "alert", Normal: Bernoulli(0.05), Anomalous: Bernoulli(0.8)},
+		},
+	}
+
+	e := p.Explain(Evidence{"requests": 100, "alert": 1})
+	if assert.Len(t, e.Evidence, 2) {
+		assert.Equal(t, "alert", e.Evidence[0].Feature)
+		assert.Equal(t, "requests", e.Evidence[1].Feature)
+		assert.InDelta(t, math.Log(0.8/0.05), e.Evidence[0].LogLikelihoodRatio, 1e-12)
+		assert.True(t, e.Evidence[1].LogLikelihoodRatio < 0, "usual number of requests speaks against anomaly")
+	}
+
+	sum := e.PriorLogOdds()
+	for _, c := range e.Evidence {
+		sum += c.LogLikelihoodRatio
+	}
+	assert.InDelta(t, sum, e.LogOdds(), 1e-12)
+	assert.InDelta(t, 1/(1+math.Exp(-sum)), e.Posterior, 1e-12)
+
+	assert.Equal(t, `P(anomaly|evidence)=0.0207
+log odds of anomaly:
+    -2.944  prior P(anomaly)=0.0500
+    +2.773  alert=1
+    -3.685  requests=100
+=   -3.857  posterior
+`, e.String())
+}
+
+func TestBuckets(t *testing.T) {
+	points := []Point{{T: 12, V: 1}, {T: 14, V: 3}, {T: 31, V: 5}}
+	buckets := Buckets(points, 10)
+
+	assert.Equal(t, []Bucket{
+		{T: 10, Values: []float64{1, 3}},
+		{T: 20},
+		{T: 30, Values: []float64{5}},
+	}, buckets)
+	assert.Equal(t, 2.0, buckets[0].Count())
+	assert.Equal(t, 2.0, buckets[0].Mean())
+	assert.Equal(t, .0, buckets[1].Mean())
+	assert.Nil(t, Buckets(nil, 10))
+
+	// points out of order are grouped like ordered, and are not reordered in place
+	shuffled := []Point{{T: 31, V: 5}, {T: 12, V: 1}, {T: 14, V: 3}}
+	assert.Equal(t, buckets, Buckets(shuffled, 10))
+	assert.Equal(t, 31.0, shuffled[0].T)
+
+	assert.Panics(t, func() {
+		Buckets(points, 0)
+	})
+	assert.Panics(t, func() {
+		Buckets(points, -10)
+	})
+}
+
+func TestModel_invalid(t *testing.T) {
+	useCases := map[string]func(){
+		"normal without deviation":  func() { Normal(100, 0) },
+		"normal negative deviation": func() { Normal(100, -1) },
+		"uniform empty range":       func() { Uniform(5, 5) },
+		"uniform reversed range":    func() { Uniform(1000, 0) },
+	}
+	for name, uc := range useCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Panics(t, uc)
+		})
+	}
+}
+
+// TestPosterior_buckets scores buckets of requests, where anomaly is
+// burst of requests or no requests at all, and checks alerts against ground truth.
+func TestPosterior_buckets(t *testing.T) {
+	rnd := rand.New(rand.NewSource(0))
+	anomalies := []Segment{{From: 200, To: 209}, {From: 500, To: 519}, {From: 800, To: 809}}
+
+	var points []Point
+	for second := 0; second < 1000; second++ {
+		rate := 5
+		switch {
+		case anomalies[0].Contains(float64(second), 0):
+			rate = 20
+		case anomalies[1].Contains(float64(second), 0):
+			rate = 0
+		case anomalies[2].Contains(float64(second), 0):
+			rate = 10
+		}
+
+		for i := 0; i < rate; i++ {
+			points = append(points, Point{T: float64(second) + rnd.Float64(), V: 100 + rnd.NormFloat64()*10})
+		}
+	}
+
+	p := Posterior{
+		Prior:     0.01,
+		Threshold: 0.5,
+		Features: []Feature{
+			{Name: "requests", Normal: Poisson(50), Anomalous: Uniform(0, 1000)},
+			{Name: "latency", Normal: Normal(100, 10), Anomalous: Uniform(0, 1000)},
+		},
+	}
+
+	var scores []Score
+	for _, b := range Buckets(points, 10) {
+		evidence := Evidence{"requests": b.Count()}
+		if b.Count() > 0 {
+			evidence["latency"] = b.Mean()
+		}
+		scores = append(scores, p.Observe(b.T, evidence))
+	}
+
+	e := Evaluate(scores, anomalies, 0)
+	assert.Equal(t, Evaluation{TruePositives: 4, Detected: 3}, e)
+}
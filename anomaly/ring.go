@@ -0,0 +1,171 @@
+package anomaly
+
+import "math"
+
+// Ring is a buffer of the last n values, new value overwrites the oldest one when buffer is full.
+// Sum, mean and variance of values are maintained when values are pushed, so they are known in O(1).
+type Ring struct {
+	values []float64
+	// next is index where the next value is written
+	next    int
+	len     int
+	sum     float64
+	moments moments
+	// run counts the newest values that are equal to the newest one
+	run int
+}
+
+// NewRing returns buffer of the last n values.
+func NewRing(n int) *Ring {
+	if n < 1 {
+		panic("anomaly: ring buffer must hold at least one value")
+	}
+
+	return &Ring{values: make([]float64, n)}
+}
+
+// Push adds value, and returns the oldest value that was overwritten, when buffer was full.
+func (r *Ring) Push(v float64) (float64, bool) {
+	old, full := r.values[r.next], r.Full()
+	if r.len > 0 && v == r.Last(0) {
+		r.run++
+	} else {
+		r.run = 1
+	}
+
+	r.values[r.next] = v
+	r.next = (r.next + 1) % len(r.values)
+	r.sum += v
+	if full {
+		r.sum -= old
+		r.moments.remove(old, old)
+	} else {
+		r.len++
+	}
+	r.moments.add(v, v)
+
+	// rounding errors of updates accumulate, so once per Cap() values they are computed again from values,
+	// which is still O(1) per value on average
+	if r.next == 0 {
+		r.recompute()
+	}
+
+	return old, full
+}
+
+// recompute computes sum and moments from values in buffer.
+func (r *Ring) recompute() {
+	r.sum = 0
+	for i := 0; i < r.len; i++ {
+		r.sum += r.At(i)
+	}
+
+	mean := r.sum / float64(r.len)
+	r.moments = moments{n: float64(r.len), meanX: mean, meanY: mean}
+	for i := 0; i < r.len; i++ {
+		d := r.At(i) - mean
+		r.moments.c += d * d
+	}
+}
+
+// At returns i-th value, where 0 is the oldest value in buffer, and Len()-1 is the newest.
+func (r *Ring) At(i int) float64 {
+	if i < 0 || i >= r.len {
+		panic("anomaly: ring buffer index out of range")
+	}
+
+	start := r.next - r.len
+	if start < 0 {
+		start += len(r.values)
+	}
+
+	return r.values[(start+i)%len(r.values)]
+}
+
+// Last returns the newest value, lag 0, or value pushed lag values before it.
+func (r *Ring) Last(lag int) float64 {
+	return r.At(r.len - 1 - lag)
+}
+
+// Len returns number of values in buffer.
+func (r *Ring) Len() int {
+	return r.len
+}
+
+// Cap returns how many values buffer can hold.
+func (r *Ring) Cap() int {
+	return len(r.values)
+}
+
+// Full returns true when buffer holds Cap() values.
+func (r *Ring) Full() bool {
+	return r.len == len(r.values)
+}
+
+// Sum returns sum of values in buffer.
+func (r *Ring) Sum() float64 {
+	return r.sum
+}
+
+// Mean returns average of values in buffer.
+func (r *Ring) Mean() float64 {
+	if r.len == 0 {
+		return 0
+	}
+	if r.Constant() {
+		return r.Last(0)
+	}
+
+	return r.moments.meanX
+}
+
+// Constant returns true when all values in buffer are equal.
+func (r *Ring) Constant() bool {
+	return r.run >= r.len
+}
+
+// Variance returns sample variance of values in buffer, which is exactly zero for constant values,
+// even though updates of variance leave rounding errors.
+func (r *Ring) Variance() float64 {
+	if r.len < 2 || r.Constant() {
+		return 0
+	}
+
+	// rounding error can make variance of constant values slightly negative
+	return math.Max(0, r.moments.c/float64(r.len-1))
+}
+
+// StdDev returns sample standard deviation of values in buffer.
+func (r *Ring) StdDev() float64 {
+	return math.Sqrt(r.Variance())
+}
+
+// moments holds means of pairs of values, and their co-moment ∑(x - mean(x))(y - mean(y)),
+// which for pairs (v, v) is sum of squared deviations.
+// They are updated with Welford's algorithm, extended to removal of values from sliding window.
+// Unlike sum of squares, it doesn't lose precision when values are far from zero, like 1e8 ± 1.
+type moments struct {
+	n            float64
+	meanX, meanY float64
+	c            float64
+}
+
+func (m *moments) add(x, y float64) {
+	m.n++
+	dx := x - m.meanX
+	m.meanX += dx / m.n
+	m.meanY += (y - m.meanY) / m.n
+	m.c += dx * (y - m.meanY)
+}
+
+func (m *moments) remove(x, y float64) {
+	if m.n <= 1 {
+		*m = moments{}
+		return
+	}
+
+	m.n--
+	m.meanX -= (x - m.meanX) / m.n
+	m.c -= (x - m.meanX) * (y - m.meanY)
+	m.meanY -= (y - m.meanY) / m.n
+}
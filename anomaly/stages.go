@@ -0,0 +1,59 @@
+package anomaly
+
+import "math"
+
+// window is a stage that scores observation once window of the last n values is full.
+type window struct {
+	ring  *Ring
+	score func(r *Ring) float64
+}
+
+func (w *window) Observe(t, v float64) Score {
+	w.ring.Push(v)
+
+	s := Score{T: t, Ready: w.ring.Full()}
+	if s.Ready {
+		s.Value = w.score(w.ring)
+	}
+
+	return s
+}
+
+// Sum returns stage that sums the last n values.
+func Sum(n int) Detector {
+	return &window{ring: NewRing(n), score: (*Ring).Sum}
+}
+
+// Mean returns stage that averages the last n values, moving average.
+func Mean(n int) Detector {
+	return &window{ring: NewRing(n), score: (*Ring).Mean}
+}
+
+// Diff returns stage that subtracts value observed lag observations before, v(t) - v(t-lag).
+// Diff(1) is rate of change, and Diff(1) followed by Diff(1) is its acceleration (second difference).
+func Diff(lag int) Detector {
+	return &window{ring: NewRing(lag + 1), score: func(r *Ring) float64 {
+		return r.Last(0) - r.Last(lag)
+	}}
+}
+
+// Abs returns stage of absolute values, so that change in either direction is scored the same.
+func Abs() Detector {
+	return Map(math.Abs)
+}
+
+// Squash returns stage that maps non-negative values from [0, ∞) to [0, 1)
+//
+//	v / (1 + v)
+//
+// so that scores of different magnitudes can be compared, and a single huge value doesn't dominate average.
+func Squash() Detector {
+	return Map(func(v float64) float64 {
+		return 1 - 1/(1+v)
+	})
+}
+
+// SquashedMean returns stage that averages squashed values over the last n values.
+func SquashedMean(n int) Detector {
+	return Pipeline(Squash(), Mean(n))
+}
@@ -0,0 +1,197 @@
+// Package synthetic generates time series with injected anomalies, labeled with ground truth,
+// so that detectors of package anomaly can be benchmarked reproducibly.
+//
+// Series is a sum of components, like trend, seasonality and noise,
+// sampled at regular times. Anomalies of configurable kind, magnitude and duration are injected on top of it,
+// and time of each of them is returned as anomaly.Segment, ready to use with anomaly.Evaluate.
+// The same Generator with the same Seed always generates the same series.
+package synthetic
+
+import (
+	"fmt"
+	"github.com/widmogrod/probability-playground/anomaly"
+	"math"
+	"math/rand"
+)
+
+// Component is a model of part of signal, it returns its value at time t.
+// Random components draw from rnd, so that series is reproducible.
+type Component interface {
+	At(t float64, rnd *rand.Rand) float64
+}
+
+// ComponentFunc is an adapter that allows use of ordinary function as a Component.
+type ComponentFunc func(t float64, rnd *rand.Rand) float64
+
+func (f ComponentFunc) At(t float64, rnd *rand.Rand) float64 {
+	return f(t, rnd)
+}
+
+// Level is constant value of signal.
+func Level(level float64) Component {
+	return ComponentFunc(func(t float64, rnd *rand.Rand) float64 {
+		return level
+	})
+}
+
+// Trend is linear trend, which grows by slope every unit of time.
+func Trend(slope float64) Component {
+	return ComponentFunc(func(t float64, rnd *rand.Rand) float64 {
+		return slope * t
+	})
+}
+
+// Seasonality is sine wave that repeats every period.
+func Seasonality(period, amplitude float64) Component {
+	return ComponentFunc(func(t float64, rnd *rand.Rand) float64 {
+		return amplitude * math.Sin(2*math.Pi*t/period)
+	})
+}
+
+// Abs returns absolute value of component, like |sin(t)| which has sharp cusps.
+func Abs(c Component) Component {
+	return ComponentFunc(func(t float64, rnd *rand.Rand) float64 {
+		return math.Abs(c.At(t, rnd))
+	})
+}
+
+// Gaussian is noise from normal distribution with zero mean.
+func Gaussian(stddev float64) Component {
+	return ComponentFunc(func(t float64, rnd *rand.Rand) float64 {
+		return rnd.NormFloat64() * stddev
+	})
+}
+
+// Uniform is noise from uniform distribution on [-width/2, width/2).
+func Uniform(width float64) Component {
+	return ComponentFunc(func(t float64, rnd *rand.Rand) float64 {
+		return (rnd.Float64() - 0.5) * width
+	})
+}
+
+// Kind is type of injected anomaly.
+type Kind int
+
+const (
+	// Spike adds Magnitude to values [A1], with zero Duration it's a single value
+	Spike Kind = iota
+	// Shift adds Magnitude to values, like change of level
+	Shift
+	// Irregular breaks regularity of signal [A2], components are sampled at time
+	// moved randomly forward by up to Magnitude
+	Irregular
+	// Jitter adds gaussian noise with Magnitude standard deviation [A3]
+	Jitter
+	// Gap drops observations [A4], Magnitude is not used
+	Gap
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Spike:
+		return "spike"
+	case Shift:
+		return "shift"
+	case Irregular:
+		return "irregular"
+	case Jitter:
+		return "jitter"
+	case Gap:
+		return "gap"
+	}
+
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Injection is anomaly of a kind, that starts at time At and lasts Duration.
+type Injection struct {
+	Kind      Kind
+	At        float64
+	Duration  float64
+	Magnitude float64
+}
+
+// Segment returns time during which anomaly is injected.
+func (i Injection) Segment() anomaly.Segment {
+	return anomaly.Segment{From: i.At, To: i.At + i.Duration}
+}
+
+// Generator generates N observations, from time Start every Step, as sum of Components with injected Anomalies.
+// Zero Step means 1.
+type Generator struct {
+	Components []Component
+	Anomalies  []Injection
+	Start      float64
+	Step       float64
+	N          int
+	Seed       int64
+}
+
+// Series is generated stream with ground truth.
+type Series struct {
+	Points []anomaly.Point
+	// Labels is true for points where anomaly was injected
+	Labels []bool
+	// Anomalies holds time of each injected anomaly, in order of Generator.Anomalies
+	Anomalies []anomaly.Segment
+}
+
+// Generate returns series, the same for the same Seed.
+func (g Generator) Generate() Series {
+	rnd := rand.New(rand.NewSource(g.Seed))
+	step := g.Step
+	if step == 0 {
+		step = 1
+	}
+
+	var result Series
+	for _, a := range g.Anomalies {
+		result.Anomalies = append(result.Anomalies, a.Segment())
+	}
+
+	for i := 0; i < g.N; i++ {
+		t := g.Start + float64(i)*step
+
+		at, missing, labeled := t, false, false
+		for _, a := range g.Anomalies {
+			if !a.Segment().Contains(t, 0) {
+				continue
+			}
+
+			labeled = true
+			switch a.Kind {
+			case Irregular:
+				at += rnd.Float64() * a.Magnitude
+			case Gap:
+				missing = true
+			}
+		}
+
+		v := .0
+		for _, c := range g.Components {
+			v += c.At(at, rnd)
+		}
+
+		for _, a := range g.Anomalies {
+			if !a.Segment().Contains(t, 0) {
+				continue
+			}
+
+			switch a.Kind {
+			case Spike, Shift:
+				v += a.Magnitude
+			case Jitter:
+				v += rnd.NormFloat64() * a.Magnitude
+			}
+		}
+
+		if missing {
+			continue
+		}
+
+		result.Points = append(result.Points, anomaly.Point{T: t, V: v})
+		result.Labels = append(result.Labels, labeled)
+	}
+
+	return result
+}
@@ -0,0 +1,136 @@
+package synthetic
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/widmogrod/probability-playground/anomaly"
+	"math/rand"
+	"testing"
+)
+
+func TestGenerator(t *testing.T) {
+	g := Generator{
+		Components: []Component{Level(10), Trend(1)},
+		Anomalies: []Injection{
+			{Kind: Spike, At: 2, Magnitude: 5},
+			{Kind: Shift, At: 4, Duration: 1, Magnitude: -20},
+			{Kind: Gap, At: 7, Duration: 1},
+		},
+		N: 10,
+	}
+	s := g.Generate()
+
+	assert.Equal(t, []anomaly.Point{
+		{T: 0, V: 10},
+		{T: 1, V: 11},
+		{T: 2, V: 17},
+		{T: 3, V: 13},
+		{T: 4, V: -6},
+		{T: 5, V: -5},
+		{T: 6, V: 16},
+		{T: 9, V: 19},
+	}, s.Points)
+	assert.Equal(t, []bool{false, false, true, false, true, true, false, false}, s.Labels)
+	assert.Equal(t, []anomaly.Segment{{From: 2, To: 2}, {From: 4, To: 5}, {From: 7, To: 8}}, s.Anomalies)
+}
+
+func TestGenerator_reproducible(t *testing.T) {
+	g := Generator{
+		Components: []Component{Seasonality(10, 1), Gaussian(0.1), Uniform(0.1)},
+		Anomalies: []Injection{
+			{Kind: Irregular, At: 20, Duration: 10, Magnitude: 5},
+			{Kind: Jitter, At: 40, Duration: 10, Magnitude: 1},
+		},
+		Start: 100,
+		Step:  0.5,
+		N:     100,
+		Seed:  7,
+	}
+
+	a, b := g.Generate(), g.Generate()
+	assert.Equal(t, a, b)
+	assert.Len(t, a.Points, 100)
+	assert.Equal(t, 100.5, a.Points[1].T)
+
+	g.Seed = 8
+	assert.NotEqual(t, a.Points, g.Generate().Points)
+}
+
+func TestComponents(t *testing.T) {
+	rnd := rand.New(rand.NewSource(0))
+	assert.InDelta(t, 2.0, Seasonality(4, 2).At(1, rnd), 1e-12)
+	assert.InDelta(t, 2.0, Abs(Seasonality(4, 2)).At(3, rnd), 1e-12)
+	assert.Equal(t, "jitter", Jitter.String())
+	assert.Equal(t, "Kind(9)", Kind(9).String())
+
+	for i := 0; i < 100; i++ {
+		v := Uniform(2).At(0, rnd)
+		assert.True(t, v >= -1 && v < 1)
+	}
+}
+
+// TestBenchmark shows how detectors are benchmarked on generated series with ground truth.
+func TestBenchmark(t *testing.T) {
+	seasonal := []Component{Level(10), Trend(0.001), Seasonality(50, 1), Gaussian(0.05)}
+	useCases := map[string]struct {
+		components []Component
+		anomalies  []Injection
+		detector   func() anomaly.Detector
+		// delay is how long after anomaly alert is still true positive
+		delay float64
+	}{
+		"spikes": {
+			components: []Component{Level(10), Trend(0.001), Gaussian(0.5)},
+			anomalies: []Injection{
+				{Kind: Spike, At: 150, Magnitude: 5},
+				{Kind: Spike, At: 420, Magnitude: -5},
+				{Kind: Spike, At: 777, Magnitude: 5},
+			},
+			detector: func() anomaly.Detector {
+				return anomaly.NewSpike(30, 6)
+			},
+		},
+		"irregular": {
+			components: seasonal,
+			anomalies:  []Injection{{Kind: Irregular, At: 400, Duration: 100, Magnitude: 50}},
+			detector: func() anomaly.Detector {
+				return anomaly.NewPeriodicity(50, 50, 0.5)
+			},
+			// correlation recovers when window of pairs with values one period earlier is regular again
+			delay: 100,
+		},
+		"jitter": {
+			components: seasonal,
+			anomalies:  []Injection{{Kind: Jitter, At: 600, Duration: 30, Magnitude: 0.5}},
+			detector: func() anomaly.Detector {
+				return anomaly.Pipeline(anomaly.Diff(1), anomaly.Diff(1), anomaly.NewJitter(10, 100, 5))
+			},
+			delay: 10,
+		},
+		"gap": {
+			components: seasonal,
+			anomalies:  []Injection{{Kind: Gap, At: 300, Duration: 10}},
+			detector: func() anomaly.Detector {
+				return anomaly.NewGap(3)
+			},
+			delay: 1,
+		},
+	}
+	for name, uc := range useCases {
+		t.Run(name, func(t *testing.T) {
+			for seed := int64(0); seed < 5; seed++ {
+				s := Generator{
+					Components: uc.components,
+					Anomalies:  uc.anomalies,
+					N:          1000,
+					Seed:       seed,
+				}.Generate()
+
+				e := anomaly.Evaluate(anomaly.ObserveAll(uc.detector(), s.Points), s.Anomalies, uc.delay)
+				t.Logf("seed=%d %+v", seed, e)
+
+				assert.Equal(t, 1.0, e.Recall(), "seed=%d", seed)
+				assert.True(t, e.Precision() >= 0.9, "seed=%d precision=%v", seed, e.Precision())
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package bayes
+
+// BoW is a bag of words, it counts how many times each word occurred.
+// Total of all counts is maintained incrementally, so it's known in O(1).
+// Zero value is ready to use.
+type BoW struct {
+	counts map[string]float64
+	total  float64
+}
+
+func (b *BoW) Inc(w string) {
+	b.Add(w, 1)
+}
+
+// Add increases count of the word by n.
+func (b *BoW) Add(w string, n float64) {
+	if b.counts == nil {
+		b.counts = make(map[string]float64)
+	}
+
+	b.counts[w] += n
+	b.total += n
+}
+
+// Remove forgets the word.
+func (b *BoW) Remove(w string) {
+	b.total -= b.counts[w]
+	delete(b.counts, w)
+}
+
+func (b *BoW) Val(w string) float64 {
+	return b.counts[w]
+}
+
+// Total returns sum of counts of all words.
+func (b *BoW) Total() float64 {
+	return b.total
+}
+
+// Words returns distinct words in the bag, in no particular order.
+func (b *BoW) Words() []string {
+	result := make([]string, 0, len(b.counts))
+	for w := range b.counts {
+		result = append(result, w)
+	}
+
+	return result
+}
+
+// Len returns number of distinct words in the bag.
+func (b *BoW) Len() int {
+	return len(b.counts)
+}
+
+// scale multiplies all counts by factor.
+func (b *BoW) scale(factor float64) {
+	for w := range b.counts {
+		b.counts[w] *= factor
+	}
+	b.total *= factor
+}
+
+// Class is a label of documents, like "spam" or "ham".
+type Class = string
+
+// negligible is a count below which word or class is forgotten.
+// Counts are floats, and removing what was added leaves rounding errors instead of zero.
+const negligible = 1e-9
+
+// minScale is the smallest scale of counts, before it's applied to every count.
+// It keeps stored counts far from overflow of float64.
+const minScale = 1e-100
+
+// BowClass holds bag of words for each class, together with number of documents of each class.
+//
+// All aggregates that are needed to score a document - totals of classes,
+// number of documents and size of vocabulary - are updated incrementally,
+// so cost of scoring a document does not depend on size of vocabulary.
+//
+// Counts can be decayed, to make old documents less important than new ones.
+// Instead of multiplying every count, decay multiplies scale shared by all counts,
+// and new counts are stored divided by the scale, so decay is O(1).
+// Words which decayed count became negligible are forgotten by the next Add, so vocabulary shrinks.
+// To keep it amortised O(1), words are looked for only after scale halved since the last time.
+// Zero value is ready to use.
+type BowClass struct {
+	bags map[Class]*BoW
+	// docs holds number of documents of each class
+	docs      map[Class]float64
+	totalDocs float64
+	// vocabulary holds in how many classes each word occurred
+	vocabulary map[string]int
+	// scale of stored counts, zero means 1, so that zero value is ready to use
+	scale float64
+	// pruned is scale at which decayed words were forgotten the last time, zero means 1
+	pruned float64
+}
+
+func (bc *BowClass) bag(class Class) *BoW {
+	if bc.bags == nil {
+		bc.bags = make(map[Class]*BoW)
+		bc.docs = make(map[Class]float64)
+		bc.vocabulary = make(map[string]int)
+	}
+
+	b, ok := bc.bags[class]
+	if !ok {
+		b = &BoW{}
+		bc.bags[class] = b
+	}
+
+	return b
+}
+
+// factor returns scale of stored counts.
+func (bc *BowClass) factor() float64 {
+	if bc.scale == 0 {
+		return 1
+	}
+
+	return bc.scale
+}
+
+func (bc *BowClass) Inc(class Class, w string) {
+	bc.Add(class, w, 1)
+}
+
+// Add increases count of the word in the class by n.
+// Negative n decreases count, which never goes below zero,
+// and word with negligible count is forgotten.
+func (bc *BowClass) Add(class Class, w string, n float64) {
+	bc.prune()
+
+	b := bc.bag(class)
+	before := b.Val(w)
+	if (before+n/bc.factor())*bc.factor() <= negligible {
+		if before > 0 {
+			b.Remove(w)
+			bc.forget(w)
+		}
+		return
+	}
+
+	if before == 0 {
+		bc.vocabulary[w]++
+	}
+
+	b.Add(w, n/bc.factor())
+}
+
+// prune forgets words of all classes which decayed count is negligible,
+// when scale halved since words were pruned the last time.
+func (bc *BowClass) prune() {
+	last := bc.pruned
+	if last == 0 {
+		last = 1
+	}
+	if bc.factor() > last/2 {
+		return
+	}
+
+	bc.forgetNegligible()
+}
+
+// forgetNegligible forgets words of all classes which count is negligible, in O(|V|).
+func (bc *BowClass) forgetNegligible() {
+	for _, b := range bc.bags {
+		for w, n := range b.counts {
+			if n*bc.factor() <= negligible {
+				b.Remove(w)
+				bc.forget(w)
+			}
+		}
+	}
+	bc.pruned = bc.scale
+}
+
+// forget decrements number of classes in which word occurred.
+func (bc *BowClass) forget(w string) {
+	bc.vocabulary[w]--
+	if bc.vocabulary[w] <= 0 {
+		delete(bc.vocabulary, w)
+	}
+}
+
+// IncDoc counts one more document of the class.
+func (bc *BowClass) IncDoc(class Class) {
+	bc.AddDocs(class, 1)
+}
+
+// AddDocs increases number of documents of the class by n.
+// Negative n decreases number of documents, which never goes below zero,
+// and class without documents and words is forgotten.
+func (bc *BowClass) AddDocs(class Class, n float64) {
+	b := bc.bag(class)
+	before := bc.docs[class]
+	after := before + n/bc.factor()
+	if after*bc.factor() <= negligible {
+		after = 0
+	}
+
+	bc.docs[class] = after
+	bc.totalDocs += after - before
+
+	if after == 0 && b.Len() == 0 && n < 0 {
+		delete(bc.bags, class)
+		delete(bc.docs, class)
+	}
+}
+
+// Decay multiplies counts of all words and documents by factor, in O(1).
+func (bc *BowClass) Decay(factor float64) {
+	bc.scale = bc.factor() * factor
+	if bc.scale >= minScale {
+		return
+	}
+
+	for _, b := range bc.bags {
+		b.scale(bc.scale)
+	}
+	for class := range bc.docs {
+		bc.docs[class] *= bc.scale
+	}
+	bc.totalDocs *= bc.scale
+	bc.scale = 1
+
+	// counts are rescaled in O(|V|) anyway, so negligible words are forgotten right away
+	bc.forgetNegligible()
+}
+
+func (bc *BowClass) Val(class Class, w string) float64 {
+	if b, ok := bc.bags[class]; ok {
+		return b.Val(w) * bc.factor()
+	}
+
+	return 0
+}
+
+// Has returns true when word occurred in any of classes.
+func (bc *BowClass) Has(w string) bool {
+	return bc.vocabulary[w] > 0
+}
+
+// Vocabulary returns number of distinct words that occurred in any of classes.
+func (bc *BowClass) Vocabulary() int {
+	return len(bc.vocabulary)
+}
+
+func (bc *BowClass) Total(class Class) float64 {
+	if b, ok := bc.bags[class]; ok {
+		return b.Total() * bc.factor()
+	}
+
+	return 0
+}
+
+// Docs returns number of documents of the class.
+func (bc *BowClass) Docs(class Class) float64 {
+	return bc.docs[class] * bc.factor()
+}
+
+// TotalDocs returns number of documents of all classes.
+func (bc *BowClass) TotalDocs() float64 {
+	return bc.totalDocs * bc.factor()
+}
+
+// Words returns distinct words of the class, in no particular order.
+func (bc *BowClass) Words(class Class) []string {
+	if b, ok := bc.bags[class]; ok {
+		return b.Words()
+	}
+
+	return nil
+}
+
+// Classes returns classes that have bag of words, in no particular order.
+func (bc *BowClass) Classes() []Class {
+	result := make([]Class, 0, len(bc.bags))
+	for class := range bc.bags {
+		result = append(result, class)
+	}
+
+	return result
+}
+
+func (bc *BowClass) Proportion(class Class, w string) float64 {
+	return bc.Val(class, w) / bc.Total(class)
+}
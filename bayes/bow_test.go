@@ -0,0 +1,138 @@
+package bayes
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+)
+
+func TestBowClass(t *testing.T) {
+	bc := BowClass{}
+	bc.Inc("spam", "password")
+	bc.Inc("spam", "password")
+	bc.Inc("spam", "send")
+	bc.Inc("ham", "send")
+	bc.IncDoc("spam")
+	bc.IncDoc("ham")
+	bc.IncDoc("ham")
+
+	assert.Equal(t, 3.0, bc.Total("spam"))
+	assert.Equal(t, 1.0, bc.Total("ham"))
+	assert.Equal(t, 2.0, bc.Val("spam", "password"))
+	assert.Equal(t, .0, bc.Val("ham", "password"))
+	assert.Equal(t, .0, bc.Val("unknown", "password"))
+	assert.Equal(t, 2.0/3, bc.Proportion("spam", "password"))
+
+	assert.True(t, bc.Has("send"))
+	assert.False(t, bc.Has("picture"))
+	assert.Equal(t, 2, bc.Vocabulary())
+
+	assert.Equal(t, 1.0, bc.Docs("spam"))
+	assert.Equal(t, 3.0, bc.TotalDocs())
+	assert.ElementsMatch(t, []Class{"spam", "ham"}, bc.Classes())
+}
+
+func TestBowClass_remove(t *testing.T) {
+	bc := BowClass{}
+	bc.Inc("spam", "password")
+	bc.Inc("ham", "password")
+	bc.IncDoc("spam")
+
+	bc.Add("spam", "password", -1)
+	assert.Equal(t, .0, bc.Val("spam", "password"))
+	assert.Equal(t, .0, bc.Total("spam"))
+	assert.True(t, bc.Has("password"), "word is still known from ham")
+
+	// count never goes below zero
+	bc.Add("ham", "password", -5)
+	assert.Equal(t, .0, bc.Val("ham", "password"))
+	assert.False(t, bc.Has("password"))
+	assert.Equal(t, 0, bc.Vocabulary())
+
+	// class without documents and words is forgotten
+	bc.AddDocs("spam", -1)
+	assert.Equal(t, .0, bc.TotalDocs())
+	assert.Equal(t, []Class{"ham"}, bc.Classes())
+}
+
+func TestBowClass_Decay(t *testing.T) {
+	bc := BowClass{}
+	bc.Inc("spam", "password")
+	bc.Inc("spam", "send")
+	bc.IncDoc("spam")
+
+	bc.Decay(0.5)
+	assert.Equal(t, 0.5, bc.Val("spam", "password"))
+	assert.Equal(t, 1.0, bc.Total("spam"))
+	assert.Equal(t, 0.5, bc.Docs("spam"))
+
+	// new counts are not decayed
+	bc.Inc("spam", "password")
+	bc.IncDoc("spam")
+	assert.Equal(t, 1.5, bc.Val("spam", "password"))
+	assert.Equal(t, 2.0, bc.Total("spam"))
+	assert.Equal(t, 1.5, bc.TotalDocs())
+
+	// scale smaller than minScale is applied to counts, which must give the same values
+	for i := 0; i < 400; i++ {
+		bc.Decay(0.5)
+		bc.Inc("spam", "password")
+	}
+	assert.InDelta(t, 2.0, bc.Val("spam", "password"), 1e-9)
+	assert.InDelta(t, 2.0, bc.Total("spam"), 1e-9)
+	assert.True(t, bc.factor() >= minScale)
+}
+
+func TestBowClass_Decay_forgetsNegligible(t *testing.T) {
+	bc := BowClass{}
+	bc.Inc("spam", "password")
+	bc.Inc("spam", "send")
+	bc.Inc("ham", "send")
+	bc.IncDoc("spam")
+
+	bc.Decay(1e-10)
+	assert.Equal(t, 2, bc.Vocabulary())
+
+	// decayed words are forgotten by the next Add, in every class
+	bc.Inc("spam", "password")
+	assert.Equal(t, 1, bc.Vocabulary())
+	assert.False(t, bc.Has("send"))
+	assert.Equal(t, .0, bc.Val("ham", "send"))
+	assert.Equal(t, []string{"password"}, bc.Words("spam"))
+	assert.InDelta(t, 1.0, bc.Total("spam"), 1e-9)
+	assert.Equal(t, .0, bc.Total("ham"))
+
+	// words are forgotten also when scale is applied to counts
+	for i := 0; i < 400; i++ {
+		bc.Decay(0.5)
+	}
+	bc.Inc("ham", "picture")
+	assert.Equal(t, 1, bc.Vocabulary())
+	assert.Equal(t, []string{"picture"}, bc.Words("ham"))
+}
+
+// BenchmarkClassifier_Predict shows that cost of scoring a document
+// does not depend on size of vocabulary, only on length of the document.
+func BenchmarkClassifier_Predict(b *testing.B) {
+	for _, vocabulary := range []int{100, 10000, 1000000} {
+		c := &Classifier{Alpha: 1}
+		var even, odd []string
+		for i := 0; i < vocabulary; i++ {
+			if i%2 == 0 {
+				even = append(even, strconv.Itoa(i))
+			} else {
+				odd = append(odd, strconv.Itoa(i))
+			}
+		}
+		c.Train("even", even)
+		c.Train("odd", odd)
+
+		message := append(even[:25:25], odd[:25]...)
+		b.Run(fmt.Sprintf("vocabulary=%d", vocabulary), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				c.Predict(message)
+			}
+		})
+	}
+}
@@ -0,0 +1,333 @@
+// Package bayes implements naive Bayes classifier of documents, like spam filter.
+//
+// Classifier applies Bayes theorem to a document, which is a list of words,
+// assuming that words are independent of each other given class (naive assumption)
+//
+//	                 P(C) * P(w1|C) * ... * P(wn|C)
+//	P(C|w1..wn) = ------------------------------------
+//	               ∑ P(Ci) * P(w1|Ci) * ... * P(wn|Ci)
+//
+// Product of many small probabilities quickly underflows float64,
+// that's why score of each class is computed as sum of logarithms.
+package bayes
+
+import (
+	"fmt"
+	"github.com/widmogrod/probability-playground/bayes/tokenize"
+	"math"
+	"sort"
+	"time"
+)
+
+// UnknownWords is a policy of handling words that were never seen in training (out of vocabulary).
+type UnknownWords int
+
+const (
+	// IgnoreUnknown skips unknown words, like they were not in the document.
+	IgnoreUnknown UnknownWords = iota
+	// SmoothUnknown treats all unknown words as one extra word of vocabulary,
+	// that was never seen in any class. With smoothing it has small probability α/(total(C) + α|V|)
+	// in each class, so long message full of unknown words is less probable in class with many words.
+	SmoothUnknown
+)
+
+// Classifier is naive Bayes classifier,
+// which learns probability of words in each class from counts of words in training documents.
+// Variant decides how the counts are turned into probability of document, multinomial by default.
+// Zero value is ready to use.
+type Classifier struct {
+	// Alpha is additive smoothing of counts of words:
+	// 1 is Laplace smoothing, value between 0 and 1 is Lidstone smoothing, and zero disables smoothing.
+	// Without smoothing single word that was not seen in a class makes the class impossible,
+	// and document impossible in every class gets prior probabilities.
+	Alpha float64
+	// Unknown is a policy of handling words that were never seen in training
+	Unknown UnknownWords
+	// Variant of naive Bayes, all variants are learned from the same training,
+	// so it can be changed at any time
+	Variant Variant
+	// Tokenizer splits text given to TrainText and PredictText into words,
+	// when nil text is split on white spaces.
+	Tokenizer tokenize.Tokenizer
+
+	words BowClass
+	// presence counts documents of each class that contain the word, used by Bernoulli variant
+	presence BowClass
+	// absences is replaced whenever counts change, so that cached values are computed again,
+	// it's a pointer so that classifier can be copied without copying its lock
+	absences *absenceCache
+}
+
+// Train learns that document belongs to the class.
+func (c *Classifier) Train(class Class, doc []string) {
+	for _, w := range doc {
+		c.words.Inc(class, w)
+	}
+	for _, w := range distinct(doc) {
+		c.presence.Inc(class, w)
+	}
+
+	c.words.IncDoc(class)
+	c.changed()
+}
+
+// TrainText learns that text belongs to the class, text is split into words with Tokenizer.
+func (c *Classifier) TrainText(class Class, text string) {
+	c.Train(class, c.tokenize(text))
+}
+
+// Unlearn forgets that document belongs to the class, it reverses Train.
+// Counts never go below zero, so document which contribution already decayed is forgotten only partially,
+// and class without documents is forgotten.
+func (c *Classifier) Unlearn(class Class, doc []string) error {
+	if c.words.Docs(class) == 0 {
+		return fmt.Errorf("bayes: can't unlearn document of class %q without documents", class)
+	}
+
+	for _, w := range doc {
+		c.words.Add(class, w, -1)
+	}
+	for _, w := range distinct(doc) {
+		c.presence.Add(class, w, -1)
+	}
+
+	c.words.AddDocs(class, -1)
+	c.changed()
+
+	return nil
+}
+
+// UnlearnText forgets that text belongs to the class, text is split into words with Tokenizer.
+func (c *Classifier) UnlearnText(class Class, text string) error {
+	return c.Unlearn(class, c.tokenize(text))
+}
+
+// Update moves document, that was learned as belonging to one class, to other class.
+// It's how user feedback, like "mark as not spam", is learned without retraining.
+func (c *Classifier) Update(from, to Class, doc []string) error {
+	if err := c.Unlearn(from, doc); err != nil {
+		return err
+	}
+
+	c.Train(to, doc)
+
+	return nil
+}
+
+// UpdateText moves text from one class to other, text is split into words with Tokenizer.
+func (c *Classifier) UpdateText(from, to Class, text string) error {
+	return c.Update(from, to, c.tokenize(text))
+}
+
+// Decay multiplies counts of words and documents learned so far by factor from (0, 1],
+// so that documents learned later have bigger weight than old ones, and classifier follows drifting vocabulary.
+// Decay is O(1), regardless of size of vocabulary.
+// Words which decayed counts became negligible are removed from vocabulary by the next Train or Unlearn.
+func (c *Classifier) Decay(factor float64) {
+	if !(factor > 0 && factor <= 1) {
+		panic(fmt.Sprintf("bayes: decay factor %v is not in (0, 1]", factor))
+	}
+
+	c.words.Decay(factor)
+	c.presence.Decay(factor)
+	c.changed()
+}
+
+// DecayFactor returns factor of exponential decay after elapsed time,
+// such that weight of documents halves every halfLife.
+func DecayFactor(elapsed, halfLife time.Duration) float64 {
+	return math.Pow(0.5, float64(elapsed)/float64(halfLife))
+}
+
+// tokenize splits text into words with Tokenizer.
+func (c *Classifier) tokenize(text string) []string {
+	if c.Tokenizer == nil {
+		return tokenize.Whitespace.Tokenize(text)
+	}
+
+	return c.Tokenizer.Tokenize(text)
+}
+
+// Classes returns sorted list of classes seen during training.
+func (c *Classifier) Classes() []Class {
+	result := c.words.Classes()
+	sort.Strings(result)
+
+	return result
+}
+
+// Prior returns probability of the class learned from number of training documents, P(C).
+func (c *Classifier) Prior(class Class) float64 {
+	if c.words.TotalDocs() == 0 {
+		return 0
+	}
+
+	return c.words.Docs(class) / c.words.TotalDocs()
+}
+
+// Likelihood returns probability of the word in documents of the class, P(w|C),
+// estimated with additive smoothing
+//
+//	P(w|C) = (count(w, C) + α) / (total(C) + α|V|)
+//
+// where |V| is size of vocabulary, so that probabilities of all words in a class sum to one.
+// Bernoulli variant returns probability that document of the class contains the word,
+// and Complement variant returns probability of the word in documents of all other classes.
+func (c *Classifier) Likelihood(class Class, w string) float64 {
+	switch c.Variant {
+	case Bernoulli:
+		return c.bernoulli(class, w)
+	case Complement:
+		return c.complement(class, w)
+	}
+
+	denominator := c.words.Total(class) + c.Alpha*float64(c.vocabulary())
+	if denominator == 0 {
+		return 0
+	}
+
+	return (c.words.Val(class, w) + c.Alpha) / denominator
+}
+
+// vocabulary returns size of vocabulary used in denominator of likelihood.
+// When unknown words are smoothed, they are counted as one extra word.
+func (c *Classifier) vocabulary() int {
+	v := c.words.Vocabulary()
+	if c.Unknown == SmoothUnknown {
+		v++
+	}
+
+	return v
+}
+
+// Posterior holds probability of each class given document, P(C|document).
+type Posterior map[Class]float64
+
+// Best returns class with the highest probability.
+// When classes are equally probable, the first in alphabetical order is returned.
+func (p Posterior) Best() (Class, float64) {
+	classes := make([]Class, 0, len(p))
+	for class := range p {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+
+	best, probability := "", math.Inf(-1)
+	for _, class := range classes {
+		if p[class] > probability {
+			best, probability = class, p[class]
+		}
+	}
+
+	return best, probability
+}
+
+// Predict returns probability of each class given the document.
+// Words that were never seen in training are handled according to Unknown policy.
+// Without smoothing document can be impossible in every class, like when each class misses one of its words,
+// such contradictory evidence says nothing about the class, so probability of each class is its prior.
+func (c *Classifier) Predict(doc []string) Posterior {
+	scores := make(map[Class]float64)
+	for _, class := range c.Classes() {
+		scores[class] = c.score(class, doc)
+	}
+
+	return c.posterior(scores)
+}
+
+// posterior normalises scores of classes, or their priors when every class is impossible.
+func (c *Classifier) posterior(scores map[Class]float64) Posterior {
+	for _, s := range scores {
+		if !math.IsInf(s, -1) {
+			return normalise(scores)
+		}
+	}
+
+	priors := make(map[Class]float64, len(scores))
+	for class := range scores {
+		priors[class] = c.logPrior(class)
+	}
+
+	return normalise(priors)
+}
+
+// PredictText returns probability of each class given text, text is split into words with Tokenizer.
+// The same Tokenizer must be used for training and prediction, otherwise words won't match.
+func (c *Classifier) PredictText(text string) Posterior {
+	return c.Predict(c.tokenize(text))
+}
+
+// score returns logarithm of unnormalised posterior, in multinomial variant
+//
+//	log(P(C)) + log(P(w1|C)) + ... + log(P(wn|C))
+//
+// and in complement variant, where the lower probability of document in complement of class, the better
+//
+//	log(1/|C|) - log(P(w1|¬C)) - ... - log(P(wn|¬C))
+func (c *Classifier) score(class Class, doc []string) float64 {
+	if c.Variant == Bernoulli {
+		return c.scoreBernoulli(class, doc)
+	}
+
+	result := c.logPrior(class)
+	for _, w := range doc {
+		if c.ignored(w) {
+			continue
+		}
+
+		result += c.evidence(class, w, 1)
+	}
+
+	return result
+}
+
+// ignored returns true when word is not scored, because it's unknown and unknown words are ignored.
+func (c *Classifier) ignored(w string) bool {
+	return !c.words.Has(w) && c.Unknown == IgnoreUnknown
+}
+
+// normalise turns logarithms of unnormalised posteriors into probabilities that sum to one.
+// Before exponentiation the biggest score is subtracted from each (log-sum-exp trick),
+// so at least one exponent is e^0 = 1 and nothing underflows to zero.
+// When every class is impossible, like classes without documents, all of them get zero probability,
+// and when some classes are certain, they share probability equally.
+func normalise(scores map[Class]float64) Posterior {
+	max := math.Inf(-1)
+	for _, s := range scores {
+		max = math.Max(max, s)
+	}
+
+	result := make(Posterior, len(scores))
+	if math.IsInf(max, -1) {
+		for class := range scores {
+			result[class] = 0
+		}
+		return result
+	}
+	if math.IsInf(max, 1) {
+		certain := .0
+		for class, s := range scores {
+			if math.IsInf(s, 1) {
+				result[class] = 1
+				certain++
+			} else {
+				result[class] = 0
+			}
+		}
+		for class := range result {
+			result[class] /= certain
+		}
+		return result
+	}
+
+	sum := .0
+	for class, s := range scores {
+		result[class] = math.Exp(s - max)
+		sum += result[class]
+	}
+	for class := range result {
+		result[class] /= sum
+	}
+
+	return result
+}
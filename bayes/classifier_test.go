@@ -0,0 +1,229 @@
+package bayes
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func train(c *Classifier, samples map[string]Class) {
+	for text, class := range samples {
+		c.Train(class, strings.Split(text, " "))
+	}
+}
+
+func TestClassifier_Predict(t *testing.T) {
+	c := &Classifier{}
+	train(c, map[string]Class{
+		"send me your password": "spam",
+		"send me your picture":  "ham",
+		"what is your password": "spam",
+		"what is your name":     "ham",
+	})
+
+	assert.Equal(t, []Class{"ham", "spam"}, c.Classes())
+	assert.Equal(t, 0.5, c.Prior("spam"))
+	assert.Equal(t, 0.25, c.Likelihood("spam", "your"))
+
+	// "name" was seen only in ham
+	p := c.Predict(strings.Split("what is your name", " "))
+	assert.InDelta(t, 1, p["ham"], 1e-12)
+	assert.InDelta(t, 0, p["spam"], 1e-12)
+
+	// unknown words don't change priors
+	p = c.Predict([]string{"unknown"})
+	assert.InDelta(t, 0.5, p["ham"], 1e-12)
+	assert.InDelta(t, 0.5, p["spam"], 1e-12)
+}
+
+func TestClassifier_Predict_manyClasses(t *testing.T) {
+	c := &Classifier{}
+	c.Train("sport", []string{"ball", "goal", "team"})
+	c.Train("sport", []string{"goal", "match"})
+	c.Train("politics", []string{"vote", "party", "team"})
+	c.Train("tech", []string{"code", "team"})
+
+	// priors are learned from number of documents
+	assert.Equal(t, 0.5, c.Prior("sport"))
+	assert.Equal(t, 0.25, c.Prior("tech"))
+
+	p := c.Predict([]string{"team"})
+	sum := .0
+	for _, probability := range p {
+		sum += probability
+	}
+	assert.InDelta(t, 1, sum, 1e-12)
+
+	// P(team|sport)=1/5, P(team|politics)=1/3, P(team|tech)=1/2
+	// weighted by priors 1/2, 1/4, 1/4
+	assert.InDelta(t, 0.1/(0.1+1.0/12+0.125), p["sport"], 1e-12)
+
+	// small class wins, because "team" makes most of its words
+	class, _ := p.Best()
+	assert.Equal(t, "tech", class)
+}
+
+func TestClassifier_Predict_longDocument(t *testing.T) {
+	c := &Classifier{}
+	c.Train("a", []string{"x", "x", "x", "y"})
+	c.Train("b", []string{"x", "y", "y", "y"})
+
+	// product of thousands probabilities underflows float64, but sum of logarithms does not
+	doc := make([]string, 5000)
+	for i := range doc {
+		doc[i] = "x"
+	}
+	doc[0] = "y"
+
+	p := c.Predict(doc)
+	assert.InDelta(t, 1, p["a"], 1e-12)
+	assert.False(t, math.IsNaN(p["b"]))
+}
+
+func TestClassifier_Likelihood_smoothing(t *testing.T) {
+	c := &Classifier{Alpha: 1}
+	c.Train("spam", []string{"send", "password"})
+	c.Train("ham", []string{"send", "picture", "picture"})
+
+	// vocabulary: send, password, picture
+	assert.Equal(t, 2.0/5, c.Likelihood("spam", "send"))
+	assert.Equal(t, 1.0/5, c.Likelihood("spam", "picture"))
+	assert.Equal(t, 1.0/6, c.Likelihood("ham", "password"))
+
+	// probabilities of vocabulary sum to one in each class
+	for _, class := range c.Classes() {
+		sum := .0
+		for _, w := range []string{"send", "password", "picture"} {
+			sum += c.Likelihood(class, w)
+		}
+		assert.InDelta(t, 1, sum, 1e-12)
+	}
+
+	c.Alpha = 0.5
+	assert.Equal(t, 1.5/3.5, c.Likelihood("spam", "send"))
+}
+
+func TestClassifier_Predict_smoothing(t *testing.T) {
+	c := &Classifier{}
+	train(c, map[string]Class{
+		"send me your password":     "spam",
+		"what is your password":     "spam",
+		"send me your picture":      "ham",
+		"what is your name":         "ham",
+		"here is picture of my dog": "ham",
+	})
+
+	// without smoothing single word never seen in spam, zeroes out its probability
+	p := c.Predict(strings.Split("send me your picture", " "))
+	assert.Equal(t, Posterior{"spam": 0, "ham": 1}, p)
+
+	// when words unseen in each class make every class impossible, posterior is the prior
+	doc := strings.Split("send me your password and picture", " ")
+	assert.Equal(t, Posterior{"spam": 0.4, "ham": 0.6}, c.Predict(doc))
+	for _, variant := range []Variant{Bernoulli, Complement} {
+		c.Variant = variant
+		p = c.Predict(doc)
+		assert.InDelta(t, 1, p["spam"]+p["ham"], 1e-12, "variant %v", variant)
+	}
+	c.Variant = Multinomial
+
+	// with smoothing, evidence of all words is weighted
+	c.Alpha = 1
+	p = c.Predict(doc)
+	assert.True(t, p["spam"] > 0.5, "P(spam)=%f", p["spam"])
+}
+
+func TestClassifier_Predict_unknownWords(t *testing.T) {
+	c := &Classifier{Alpha: 1}
+	c.Train("short", []string{"a", "b"})
+	c.Train("long", []string{"a", "b", "c", "d", "e", "f", "g", "h"})
+
+	doc := []string{"x", "y", "z"}
+
+	c.Unknown = IgnoreUnknown
+	p := c.Predict(doc)
+	assert.InDelta(t, 0.5, p["short"], 1e-12)
+
+	// unknown word is more probable in class with less words
+	c.Unknown = SmoothUnknown
+	p = c.Predict(doc)
+	assert.True(t, p["short"] > p["long"])
+	assert.Equal(t, 1.0/(2+9), c.Likelihood("short", "x"))
+}
+
+func TestClassifier_Unlearn(t *testing.T) {
+	c := &Classifier{Alpha: 1}
+	c.Train("spam", []string{"send", "password"})
+	c.Train("ham", []string{"send", "picture"})
+
+	expected := &Classifier{Alpha: 1}
+	expected.Train("spam", []string{"send", "password"})
+	expected.Train("ham", []string{"send", "picture"})
+
+	c.Train("spam", []string{"win", "money", "money"})
+	assert.NoError(t, c.Unlearn("spam", []string{"win", "money", "money"}))
+
+	doc := []string{"send", "money", "picture"}
+	assert.Equal(t, expected.Predict(doc), c.Predict(doc))
+	assert.Equal(t, expected.words.Vocabulary(), c.words.Vocabulary())
+	assert.Equal(t, expected.Prior("spam"), c.Prior("spam"))
+
+	// the last document of class is forgotten together with the class
+	assert.NoError(t, c.Unlearn("ham", []string{"send", "picture"}))
+	assert.Equal(t, []Class{"spam"}, c.Classes())
+	assert.Error(t, c.Unlearn("ham", []string{"send"}))
+}
+
+func TestClassifier_Update(t *testing.T) {
+	c := &Classifier{Alpha: 1}
+	train(c, map[string]Class{
+		"send me your password": "spam",
+		"what is your password": "spam",
+		"send me your picture":  "ham",
+	})
+
+	message := []string{"newsletter", "from", "your", "bank"}
+	c.Train("spam", message)
+	best, _ := c.Predict(message).Best()
+	assert.Equal(t, "spam", best)
+
+	// user marks message as not spam
+	assert.NoError(t, c.Update("spam", "ham", message))
+	best, _ = c.Predict(message).Best()
+	assert.Equal(t, "ham", best)
+	assert.Equal(t, 2.0/4, c.Prior("spam"))
+}
+
+func TestClassifier_Decay(t *testing.T) {
+	c := &Classifier{Alpha: 1}
+	for i := 0; i < 10; i++ {
+		c.Train("spam", []string{"lottery"})
+	}
+
+	// spammers switch vocabulary, and old messages are forgotten
+	for day := 0; day < 10; day++ {
+		c.Decay(DecayFactor(24*time.Hour, 24*time.Hour))
+		c.Train("spam", []string{"crypto"})
+		c.Train("ham", []string{"lottery"})
+	}
+
+	assert.InDelta(t, 10*math.Pow(0.5, 10), c.words.Val("spam", "lottery"), 1e-12)
+	assert.Greater(t, c.Likelihood("spam", "crypto"), c.Likelihood("spam", "lottery"))
+	best, _ := c.Predict([]string{"lottery"}).Best()
+	assert.Equal(t, "ham", best)
+
+	assert.Panics(t, func() {
+		c.Decay(0)
+	})
+	assert.Panics(t, func() {
+		c.Decay(1.5)
+	})
+}
+
+func TestDecayFactor(t *testing.T) {
+	assert.Equal(t, 1.0, DecayFactor(0, time.Hour))
+	assert.Equal(t, 0.5, DecayFactor(time.Hour, time.Hour))
+	assert.Equal(t, 0.25, DecayFactor(2*time.Hour, time.Hour))
+}
@@ -0,0 +1,57 @@
+// Package corpus reads labeled text messages from files, to train and evaluate classifiers.
+//
+// Readers stream messages one by one, so corpus doesn't have to fit in memory.
+// Each reader returns io.EOF when there are no more messages, like io.Reader does.
+package corpus
+
+import (
+	"github.com/widmogrod/probability-playground/bayes"
+	"io"
+)
+
+// Message is text labeled with its class.
+type Message struct {
+	Class bayes.Class
+	Text  string
+}
+
+// Reader streams labeled messages.
+type Reader interface {
+	// Next returns next message, or io.EOF when there are no more messages.
+	Next() (Message, error)
+}
+
+// Each calls fn for every message of reader, and stops on the first error.
+// io.EOF is the end of corpus, not an error, so it is not returned.
+func Each(r Reader, fn func(m Message) error) error {
+	for {
+		m, err := r.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := fn(m); err != nil {
+			return err
+		}
+	}
+}
+
+// Trainer learns from labeled text, like *bayes.Classifier.
+type Trainer interface {
+	TrainText(class bayes.Class, text string)
+}
+
+// Train streams all messages of reader into trainer, and returns number of messages learned.
+func Train(t Trainer, r Reader) (int, error) {
+	n := 0
+	err := Each(r, func(m Message) error {
+		t.TrainText(m.Class, m.Text)
+		n++
+		return nil
+	})
+
+	return n, err
+}
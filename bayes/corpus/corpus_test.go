@@ -0,0 +1,213 @@
+package corpus
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/widmogrod/probability-playground/bayes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// all reads all messages from reader
+func all(t *testing.T, r Reader) []Message {
+	var result []Message
+	err := Each(r, func(m Message) error {
+		result = append(result, m)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	return result
+}
+
+func TestDelimited(t *testing.T) {
+	useCases := map[string]struct {
+		reader   func() Reader
+		expected []Message
+	}{
+		"csv": {
+			reader: func() Reader {
+				return NewCSV(strings.NewReader("" +
+					"spam,send me your password\n" +
+					"ham,\"lunch, tomorrow?\"\n" +
+					"spam,\"click \"\"here\"\"\nnow\"\n"))
+			},
+			expected: []Message{
+				{Class: "spam", Text: "send me your password"},
+				{Class: "ham", Text: "lunch, tomorrow?"},
+				{Class: "spam", Text: "click \"here\"\nnow"},
+			},
+		},
+		"csv with header": {
+			reader: func() Reader {
+				r := NewCSV(strings.NewReader("label,text\nham,hello\n"))
+				r.SkipHeader = true
+				return r
+			},
+			expected: []Message{
+				{Class: "ham", Text: "hello"},
+			},
+		},
+		"tsv": {
+			reader: func() Reader {
+				return NewTSV(strings.NewReader("spam\tWIN \"free\" money, now\nham\tsee you\n"))
+			},
+			expected: []Message{
+				{Class: "spam", Text: "WIN \"free\" money, now"},
+				{Class: "ham", Text: "see you"},
+			},
+		},
+	}
+	for name, uc := range useCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, uc.expected, all(t, uc.reader()))
+		})
+	}
+}
+
+func TestDelimited_invalidRecord(t *testing.T) {
+	r := NewCSV(strings.NewReader("spam,one\nham,two,three\n"))
+
+	_, err := r.Next()
+	assert.NoError(t, err)
+	_, err = r.Next()
+	assert.Error(t, err)
+}
+
+func TestDir(t *testing.T) {
+	root, err := ioutil.TempDir("", "corpus")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	write := func(path, content string) {
+		path = filepath.Join(root, path)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+	write("spam/2.txt", "win money")
+	write("spam/1.txt", "send password")
+	write("ham/1.txt", "lunch tomorrow")
+	write("ham/.DS_Store", "hidden")
+	write("ham/nested/1.txt", "skipped")
+	write("README", "not a class")
+
+	d, err := NewDir(root)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, d.Len())
+	assert.Equal(t, []Message{
+		{Class: "ham", Text: "lunch tomorrow"},
+		{Class: "spam", Text: "send password"},
+		{Class: "spam", Text: "win money"},
+	}, all(t, d))
+
+	_, err = NewDir(filepath.Join(root, "missing"))
+	assert.Error(t, err)
+}
+
+func TestMbox(t *testing.T) {
+	mbox := "" +
+		"From spammer@example.com Mon Jan  1 00:00:00 2024\n" +
+		"From: spammer@example.com\n" +
+		"Subject: You won!\n" +
+		"\n" +
+		"Send me your password.\n" +
+		">From now on you are rich.\n" +
+		"\n" +
+		"From other@example.com Tue Jan  2 00:00:00 2024\n" +
+		"Subject: Cheap pills\n" +
+		"\n" +
+		"Buy now\n" +
+		"\n" +
+		"From broken@example.com Wed Jan  3 00:00:00 2024\n" +
+		"no headers here\n"
+
+	assert.Equal(t, []Message{
+		{Class: "spam", Text: "You won!\nSend me your password.\nFrom now on you are rich."},
+		{Class: "spam", Text: "Cheap pills\nBuy now"},
+		{Class: "spam", Text: "no headers here"},
+	}, all(t, NewMbox(strings.NewReader(mbox), "spam")))
+
+	assert.Empty(t, all(t, NewMbox(strings.NewReader(""), "spam")))
+
+	// separator as the last line, without trailing new line, and empty messages are not messages
+	useCases := map[string]string{
+		"separator at EOF":        "From a\nSubject: x\n\nbody\nFrom b",
+		"separator with new line": "From a\nSubject: x\n\nbody\nFrom b\n",
+		"empty messages":          "From a\nFrom b\n\nFrom c\nSubject: x\n\nbody\nFrom d\n\n",
+		"no trailing new line":    "From a\nSubject: x\n\nbody",
+	}
+	for name, mbox := range useCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, []Message{{Class: "spam", Text: "x\nbody"}}, all(t, NewMbox(strings.NewReader(mbox), "spam")))
+		})
+	}
+}
+
+func TestMbox_mime(t *testing.T) {
+	mbox := "" +
+		"From spammer@example.com Mon Jan  1 00:00:00 2024\n" +
+		"Subject: =?UTF-8?B?V3lncmHFgmXFmw==?= =?ISO-8859-1?Q?_caf=E9?=\n" +
+		"MIME-Version: 1.0\n" +
+		"Content-Type: multipart/mixed; boundary=\"outer\"\n" +
+		"\n" +
+		"preamble is skipped\n" +
+		"--outer\n" +
+		"Content-Type: multipart/alternative; boundary=\"inner\"\n" +
+		"\n" +
+		"--inner\n" +
+		"Content-Type: text/plain; charset=utf-8\n" +
+		"Content-Transfer-Encoding: base64\n" +
+		"\n" +
+		"U2VuZCBtZSB5b3Vy\n" +
+		"IHBhc3N3b3Jk\n" +
+		"--inner\n" +
+		"Content-Type: text/html; charset=utf-8\n" +
+		"Content-Transfer-Encoding: quoted-printable\n" +
+		"\n" +
+		"<p>Send me your =\n" +
+		"password</p>\n" +
+		"--inner--\n" +
+		"--outer\n" +
+		"Content-Type: application/octet-stream\n" +
+		"Content-Transfer-Encoding: base64\n" +
+		"\n" +
+		"AAECAwQF\n" +
+		"--outer--\n" +
+		"\n" +
+		"From other@example.com Tue Jan  2 00:00:00 2024\n" +
+		"Subject: Cheap pills\n" +
+		"Content-Transfer-Encoding: base64\n" +
+		"\n" +
+		"QnV5IG5vdw==\n"
+
+	assert.Equal(t, []Message{
+		{Class: "spam", Text: "Wygrałeś café\nSend me your password\n<p>Send me your password</p>"},
+		{Class: "spam", Text: "Cheap pills\nBuy now"},
+	}, all(t, NewMbox(strings.NewReader(mbox), "spam")))
+}
+
+func TestTrain(t *testing.T) {
+	c := &bayes.Classifier{}
+	n, err := Train(c, NewCSV(strings.NewReader("spam,send me your password\nham,see you at lunch\n")))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []bayes.Class{"ham", "spam"}, c.Classes())
+
+	best, _ := c.PredictText("password").Best()
+	assert.Equal(t, "spam", best)
+}
+
+func TestEach_stopsOnError(t *testing.T) {
+	stop := errors.New("stop")
+	calls := 0
+	err := Each(NewCSV(strings.NewReader("spam,a\nham,b\n")), func(m Message) error {
+		calls++
+		return stop
+	})
+
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,60 @@
+package corpus
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// Delimited reads messages from CSV or TSV file, with class in the first column and text in the second.
+type Delimited struct {
+	// SkipHeader ignores the first record, when it holds names of columns
+	SkipHeader bool
+
+	r    *csv.Reader
+	read bool
+}
+
+// NewCSV returns reader of comma separated "class,text" records.
+// Text with commas, quotes or new lines must be quoted, like in any CSV file.
+func NewCSV(r io.Reader) *Delimited {
+	return newDelimited(r, ',')
+}
+
+// NewTSV returns reader of tab separated "class<TAB>text" records.
+// Quotes have no special meaning, so text can contain anything except tabs and new lines.
+func NewTSV(r io.Reader) *Delimited {
+	d := newDelimited(r, '\t')
+	d.r.LazyQuotes = true
+
+	return d
+}
+
+func newDelimited(r io.Reader, comma rune) *Delimited {
+	cr := csv.NewReader(r)
+	cr.Comma = comma
+	cr.FieldsPerRecord = 2
+	cr.ReuseRecord = true
+
+	return &Delimited{r: cr}
+}
+
+func (d *Delimited) Next() (Message, error) {
+	if d.SkipHeader && !d.read {
+		d.read = true
+		if _, err := d.r.Read(); err != nil {
+			return Message{}, err
+		}
+	}
+	d.read = true
+
+	record, err := d.r.Read()
+	if err == io.EOF {
+		return Message{}, err
+	}
+	if err != nil {
+		return Message{}, fmt.Errorf("corpus: %v", err)
+	}
+
+	return Message{Class: record[0], Text: record[1]}, nil
+}
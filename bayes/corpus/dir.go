@@ -0,0 +1,82 @@
+package corpus
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+)
+
+// Dir reads messages stored one per file, in directory named after class of messages, like:
+//
+//	corpus/spam/0001.txt
+//	corpus/ham/0001.txt
+//
+// Only names of files are listed up-front, content is read when message is requested.
+type Dir struct {
+	files []file
+}
+
+type file struct {
+	class string
+	path  string
+}
+
+// NewDir lists messages in subdirectories of root, in alphabetical order of classes and files.
+// Hidden files and nested subdirectories are skipped.
+func NewDir(root string) (*Dir, error) {
+	classes, err := ioutil.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("corpus: %v", err)
+	}
+
+	d := &Dir{}
+	for _, class := range classes {
+		if !class.IsDir() || hidden(class.Name()) {
+			continue
+		}
+
+		messages, err := ioutil.ReadDir(filepath.Join(root, class.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("corpus: %v", err)
+		}
+
+		for _, m := range messages {
+			if m.IsDir() || hidden(m.Name()) {
+				continue
+			}
+
+			d.files = append(d.files, file{
+				class: class.Name(),
+				path:  filepath.Join(root, class.Name(), m.Name()),
+			})
+		}
+	}
+
+	return d, nil
+}
+
+func hidden(name string) bool {
+	return len(name) > 0 && name[0] == '.'
+}
+
+// Len returns number of messages that were not read yet.
+func (d *Dir) Len() int {
+	return len(d.files)
+}
+
+func (d *Dir) Next() (Message, error) {
+	if len(d.files) == 0 {
+		return Message{}, io.EOF
+	}
+
+	f := d.files[0]
+	d.files = d.files[1:]
+
+	text, err := ioutil.ReadFile(f.path)
+	if err != nil {
+		return Message{}, fmt.Errorf("corpus: %v", err)
+	}
+
+	return Message{Class: f.class, Text: string(text)}, nil
+}
@@ -0,0 +1,173 @@
+package corpus
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"github.com/widmogrod/probability-playground/bayes"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+)
+
+// Mbox reads e-mail messages of one class from mbox file, like spam folder exported from mail client.
+// Messages are separated by lines starting with "From ", and lines of body that start with ">From "
+// are unescaped (mboxrd). Text of message is its subject followed by body, other headers are skipped.
+// Encoded subject is decoded, body is decoded from base64 or quoted-printable,
+// and of multipart message only text parts are kept, attachments are skipped.
+type Mbox struct {
+	class bayes.Class
+	r     *bufio.Reader
+	// started is true after the first separator, content before it is not a message
+	started bool
+	err     error
+}
+
+// NewMbox returns reader of messages of the class from mbox file.
+func NewMbox(r io.Reader, class bayes.Class) *Mbox {
+	return &Mbox{class: class, r: bufio.NewReader(r)}
+}
+
+// Next returns the next message, messages without subject and body are skipped.
+func (m *Mbox) Next() (Message, error) {
+	if m.err != nil {
+		return Message{}, m.err
+	}
+
+	buf := &bytes.Buffer{}
+	for {
+		line, err := m.r.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			m.err = fmt.Errorf("corpus: %v", err)
+			return Message{}, m.err
+		}
+		if err == io.EOF {
+			// separator can be the last line, so EOF is recorded before message is returned
+			m.err = io.EOF
+		}
+
+		if isSeparator(line) {
+			if msg, ok := m.message(buf); ok {
+				return msg, nil
+			}
+			m.started = true
+		} else if m.started {
+			buf.Write(unescape(line))
+		}
+
+		if m.err != nil {
+			if msg, ok := m.message(buf); ok {
+				return msg, nil
+			}
+			return Message{}, m.err
+		}
+	}
+}
+
+// message returns message read into buffer, when it's not empty.
+func (m *Mbox) message(buf *bytes.Buffer) (Message, bool) {
+	if !m.started {
+		return Message{}, false
+	}
+
+	t := text(buf.Bytes())
+	buf.Reset()
+
+	return Message{Class: m.class, Text: t}, t != ""
+}
+
+func isSeparator(line []byte) bool {
+	return bytes.HasPrefix(line, []byte("From "))
+}
+
+// unescape removes one '>' from lines like ">From " or ">>From ", that were escaped when message was saved.
+func unescape(line []byte) []byte {
+	unquoted := bytes.TrimLeft(line, ">")
+	if len(unquoted) < len(line) && bytes.HasPrefix(unquoted, []byte("From ")) {
+		return line[1:]
+	}
+
+	return line
+}
+
+// text returns decoded subject and text of body of message,
+// or whole message when it has no valid headers.
+func text(raw []byte) string {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return strings.TrimSpace(string(raw))
+	}
+
+	body, err := decode(textproto.MIMEHeader(msg.Header), msg.Body)
+	if err != nil {
+		return strings.TrimSpace(string(raw))
+	}
+
+	subject := msg.Header.Get("Subject")
+	if decoded, err := (&mime.WordDecoder{}).DecodeHeader(subject); err == nil {
+		subject = decoded
+	}
+	if subject == "" {
+		return body
+	}
+
+	return strings.TrimSpace(subject + "\n" + body)
+}
+
+// decode returns text of body with the header, texts of parts of multipart body are joined with new line,
+// and body that is not text is empty.
+func decode(header textproto.MIMEHeader, r io.Reader) (string, error) {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		// without valid content type, body is plain text
+		mediaType = "text/plain"
+	}
+
+	switch strings.ToLower(header.Get("Content-Transfer-Encoding")) {
+	case "base64":
+		r = base64.NewDecoder(base64.StdEncoding, r)
+	case "quoted-printable":
+		r = quotedprintable.NewReader(r)
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		var texts []string
+		parts := multipart.NewReader(r, params["boundary"])
+		for {
+			part, err := parts.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return "", err
+			}
+
+			t, err := decode(part.Header, part)
+			if err != nil {
+				return "", err
+			}
+			if t != "" {
+				texts = append(texts, t)
+			}
+		}
+
+		return strings.Join(texts, "\n"), nil
+	}
+
+	if !strings.HasPrefix(mediaType, "text/") {
+		return "", nil
+	}
+
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(body)), nil
+}
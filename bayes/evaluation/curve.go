@@ -0,0 +1,106 @@
+package evaluation
+
+import (
+	"github.com/widmogrod/probability-playground/bayes"
+	"math"
+	"sort"
+)
+
+// Point of a curve, computed when documents with probability of positive class
+// greater or equal to Threshold are predicted as positive.
+// The first point of curve has infinite threshold, that no document reaches,
+// even one with probability 1.
+type Point struct {
+	Threshold float64
+	X, Y      float64
+}
+
+// Curve is a list of points ordered by decreasing threshold.
+type Curve []Point
+
+// AUC returns area under the curve, computed with trapezoidal rule.
+func (c Curve) AUC() float64 {
+	area := .0
+	for i := 1; i < len(c); i++ {
+		area += (c[i].X - c[i-1].X) * (c[i].Y + c[i-1].Y) / 2
+	}
+
+	return area
+}
+
+// counts holds number of true and false positives at each distinct threshold,
+// from the highest to the lowest.
+type counts struct {
+	threshold float64
+	tp, fp    int
+}
+
+// cumulative sorts predictions by probability of positive class,
+// and counts true and false positives when threshold is lowered to each distinct probability.
+// Documents with equal probability can't be separated by any threshold, that's why they are counted together.
+func (p Predictions) cumulative(positive bayes.Class) (result []counts, positives, negatives int) {
+	sorted := append(Predictions{}, p...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Posterior[positive] > sorted[j].Posterior[positive]
+	})
+
+	tp, fp := 0, 0
+	for i, pr := range sorted {
+		if pr.Actual == positive {
+			tp++
+		} else {
+			fp++
+		}
+
+		score := pr.Posterior[positive]
+		if i+1 < len(sorted) && sorted[i+1].Posterior[positive] == score {
+			continue
+		}
+
+		result = append(result, counts{threshold: score, tp: tp, fp: fp})
+	}
+
+	return result, tp, fp
+}
+
+// ROC returns receiver operating characteristic curve of positive class,
+// with false positive rate on X and true positive rate (recall) on Y.
+// Curve starts at (0, 0), when nothing is predicted as positive, and ends at (1, 1).
+func (p Predictions) ROC(positive bayes.Class) Curve {
+	cs, positives, negatives := p.cumulative(positive)
+
+	result := Curve{{Threshold: math.Inf(1), X: 0, Y: 0}}
+	for _, c := range cs {
+		result = append(result, Point{
+			Threshold: c.threshold,
+			X:         ratio(c.fp, negatives),
+			Y:         ratio(c.tp, positives),
+		})
+	}
+
+	return result
+}
+
+// AUC returns area under ROC curve of positive class,
+// which is probability that random document of positive class gets higher score than random negative document.
+// 0.5 means that classifier is not better than a coin toss.
+func (p Predictions) AUC(positive bayes.Class) float64 {
+	return p.ROC(positive).AUC()
+}
+
+// PrecisionRecall returns precision-recall curve of positive class, with recall on X and precision on Y.
+// Unlike ROC it shows how many false alarms are raised, which matters when positive class is rare.
+func (p Predictions) PrecisionRecall(positive bayes.Class) Curve {
+	cs, positives, _ := p.cumulative(positive)
+
+	result := Curve{{Threshold: math.Inf(1), X: 0, Y: 1}}
+	for _, c := range cs {
+		result = append(result, Point{
+			Threshold: c.threshold,
+			X:         ratio(c.tp, positives),
+			Y:         ratio(c.tp, c.tp+c.fp),
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,117 @@
+// Package evaluation measures how well classifier works on labeled documents.
+//
+// Classifier is trained and tested on different documents with k-fold cross validation,
+// and its predictions are summarised with confusion matrix, precision, recall, F1, log-loss,
+// and ROC and precision-recall curves.
+package evaluation
+
+import (
+	"errors"
+	"github.com/widmogrod/probability-playground/bayes"
+	"math/rand"
+	"sort"
+)
+
+// Model is classifier that can be evaluated, like *bayes.Classifier.
+type Model interface {
+	Train(class bayes.Class, doc []string)
+	Predict(doc []string) bayes.Posterior
+}
+
+// Sample is a document labeled with its class.
+type Sample struct {
+	Class bayes.Class
+	Doc   []string
+}
+
+// Prediction holds posterior predicted for document of Actual class.
+type Prediction struct {
+	Actual    bayes.Class
+	Posterior bayes.Posterior
+}
+
+// Predicted returns the most probable class.
+func (p Prediction) Predicted() bayes.Class {
+	class, _ := p.Posterior.Best()
+	return class
+}
+
+// Predictions is a list of predictions made for test documents.
+type Predictions []Prediction
+
+// Evaluate predicts class of each test sample with already trained model.
+func Evaluate(m Model, test []Sample) Predictions {
+	result := make(Predictions, 0, len(test))
+	for _, s := range test {
+		result = append(result, Prediction{
+			Actual:    s.Class,
+			Posterior: m.Predict(s.Doc),
+		})
+	}
+
+	return result
+}
+
+// CrossValidate splits samples into k folds, and for each fold trains new model on the remaining folds
+// and predicts classes of samples in the fold, so every sample is predicted exactly once,
+// by model that has never seen it.
+//
+// Folds are stratified, each has similar proportion of classes as all samples,
+// which matters when one class is rare. Samples are shuffled with seed, so result is reproducible.
+func CrossValidate(samples []Sample, k int, seed int64, newModel func() Model) (Predictions, error) {
+	if k < 2 {
+		return nil, errors.New("evaluation: cross validation needs at least 2 folds")
+	}
+	if k > len(samples) {
+		return nil, errors.New("evaluation: more folds than samples")
+	}
+
+	folds := stratify(samples, k, rand.New(rand.NewSource(seed)))
+
+	var result Predictions
+	for i := range folds {
+		m := newModel()
+		for j, fold := range folds {
+			if i == j {
+				continue
+			}
+			for _, s := range fold {
+				m.Train(s.Class, s.Doc)
+			}
+		}
+
+		result = append(result, Evaluate(m, folds[i])...)
+	}
+
+	return result, nil
+}
+
+// stratify shuffles samples of each class and deals them to folds in turns.
+func stratify(samples []Sample, k int, rnd *rand.Rand) [][]Sample {
+	byClass := make(map[bayes.Class][]Sample)
+	for _, s := range samples {
+		byClass[s.Class] = append(byClass[s.Class], s)
+	}
+
+	classes := make([]bayes.Class, 0, len(byClass))
+	for class := range byClass {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+
+	folds := make([][]Sample, k)
+	next := 0
+	for _, class := range classes {
+		group := byClass[class]
+		rnd.Shuffle(len(group), func(i, j int) {
+			group[i], group[j] = group[j], group[i]
+		})
+
+		for _, s := range group {
+			folds[next] = append(folds[next], s)
+			next = (next + 1) % k
+		}
+	}
+
+	return folds
+}
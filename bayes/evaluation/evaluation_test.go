@@ -0,0 +1,155 @@
+package evaluation
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/widmogrod/probability-playground/bayes"
+	"math"
+	"testing"
+)
+
+// predictions returns predictions of spam filter, for given probabilities of spam
+func predictions(actual []bayes.Class, pSpam []float64) Predictions {
+	result := make(Predictions, len(actual))
+	for i := range actual {
+		result[i] = Prediction{
+			Actual:    actual[i],
+			Posterior: bayes.Posterior{"spam": pSpam[i], "ham": 1 - pSpam[i]},
+		}
+	}
+
+	return result
+}
+
+func TestConfusionMatrix(t *testing.T) {
+	p := predictions(
+		[]bayes.Class{"spam", "spam", "spam", "ham", "ham", "ham", "ham"},
+		[]float64{0.9, 0.8, 0.3, 0.6, 0.2, 0.1, 0.1},
+	)
+
+	m := p.ConfusionMatrix()
+	assert.Equal(t, []bayes.Class{"ham", "spam"}, m.Classes)
+	assert.Equal(t, 2, m.Count("spam", "spam"))
+	assert.Equal(t, 1, m.Count("spam", "ham"))
+	assert.Equal(t, 1, m.Count("ham", "spam"))
+	assert.Equal(t, 3, m.Count("ham", "ham"))
+
+	assert.Equal(t, 5.0/7, m.Accuracy())
+	assert.Equal(t, 2.0/3, m.Precision("spam"))
+	assert.Equal(t, 2.0/3, m.Recall("spam"))
+	assert.InDelta(t, 2.0/3, m.F1("spam"), 1e-12)
+	assert.Equal(t, 3.0/4, m.Recall("ham"))
+	assert.Equal(t, 0.0, m.F1("unknown"))
+
+	assert.Equal(t, ""+
+		"actual\\predicted              ham             spam\n"+
+		"ham                             3                1\n"+
+		"spam                            1                2", m.String())
+}
+
+func TestPredictions_LogLoss(t *testing.T) {
+	p := predictions([]bayes.Class{"spam", "ham"}, []float64{0.5, 0.5})
+	assert.InDelta(t, math.Log(2), p.LogLoss(), 1e-12)
+
+	perfect := predictions([]bayes.Class{"spam", "ham"}, []float64{1, 0})
+	assert.Equal(t, 0.0, perfect.LogLoss())
+
+	// confident mistake is clipped, instead of infinite
+	wrong := predictions([]bayes.Class{"spam"}, []float64{0})
+	assert.InDelta(t, -math.Log(minProbability), wrong.LogLoss(), 1e-9)
+}
+
+func TestPredictions_ROC(t *testing.T) {
+	useCases := map[string]struct {
+		actual []bayes.Class
+		pSpam  []float64
+		auc    float64
+	}{
+		"perfect": {
+			actual: []bayes.Class{"spam", "spam", "ham", "ham"},
+			pSpam:  []float64{0.9, 0.8, 0.2, 0.1},
+			auc:    1,
+		},
+		"inverted": {
+			actual: []bayes.Class{"spam", "spam", "ham", "ham"},
+			pSpam:  []float64{0.1, 0.2, 0.8, 0.9},
+			auc:    0,
+		},
+		"ties count as half": {
+			actual: []bayes.Class{"spam", "ham"},
+			pSpam:  []float64{0.5, 0.5},
+			auc:    0.5,
+		},
+		"one of four pairs is ordered wrong": {
+			actual: []bayes.Class{"spam", "spam", "ham", "ham"},
+			pSpam:  []float64{0.9, 0.4, 0.6, 0.1},
+			auc:    0.75,
+		},
+	}
+	for name, uc := range useCases {
+		t.Run(name, func(t *testing.T) {
+			p := predictions(uc.actual, uc.pSpam)
+			roc := p.ROC("spam")
+
+			assert.Equal(t, Point{Threshold: math.Inf(1), X: 0, Y: 0}, roc[0])
+			assert.Equal(t, 1.0, roc[len(roc)-1].X)
+			assert.Equal(t, 1.0, roc[len(roc)-1].Y)
+			assert.InDelta(t, uc.auc, p.AUC("spam"), 1e-12)
+		})
+	}
+}
+
+func TestPredictions_PrecisionRecall(t *testing.T) {
+	p := predictions(
+		[]bayes.Class{"spam", "ham", "spam", "ham"},
+		[]float64{1, 0.7, 0.6, 0.1},
+	)
+
+	// document with probability 1 is predicted as positive only after the first point
+	assert.Equal(t, Curve{
+		{Threshold: math.Inf(1), X: 0, Y: 1},
+		{Threshold: 1, X: 0.5, Y: 1},
+		{Threshold: 0.7, X: 0.5, Y: 0.5},
+		{Threshold: 0.6, X: 1, Y: 2.0 / 3},
+		{Threshold: 0.1, X: 1, Y: 0.5},
+	}, p.PrecisionRecall("spam"))
+}
+
+func TestCrossValidate(t *testing.T) {
+	var samples []Sample
+	for i := 0; i < 30; i++ {
+		samples = append(samples,
+			Sample{Class: "spam", Doc: []string{"win", "money", "now"}},
+			Sample{Class: "ham", Doc: []string{"lunch", "tomorrow", "now"}},
+		)
+	}
+	// rare class
+	for i := 0; i < 5; i++ {
+		samples = append(samples, Sample{Class: "phishing", Doc: []string{"your", "password"}})
+	}
+
+	trained := 0
+	newModel := func() Model {
+		trained++
+		return &bayes.Classifier{Alpha: 1}
+	}
+
+	p, err := CrossValidate(samples, 5, 0, newModel)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, trained)
+	assert.Len(t, p, len(samples))
+
+	// stratified folds always have example of rare class in training
+	m := p.ConfusionMatrix()
+	assert.Equal(t, 1.0, m.Accuracy())
+	assert.Equal(t, 5, m.Count("phishing", "phishing"))
+	assert.Equal(t, 1.0, p.AUC("spam"))
+
+	again, err := CrossValidate(samples, 5, 0, newModel)
+	assert.NoError(t, err)
+	assert.Equal(t, p, again)
+
+	_, err = CrossValidate(samples, 1, 0, newModel)
+	assert.Error(t, err)
+	_, err = CrossValidate(samples[:3], 4, 0, newModel)
+	assert.Error(t, err)
+}
@@ -0,0 +1,143 @@
+package evaluation
+
+import (
+	"fmt"
+	"github.com/widmogrod/probability-playground/bayes"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ConfusionMatrix counts how many documents of each actual class were predicted as each class.
+type ConfusionMatrix struct {
+	// Classes holds sorted actual and predicted classes
+	Classes []bayes.Class
+	counts  map[bayes.Class]map[bayes.Class]int
+	total   int
+}
+
+// ConfusionMatrix compares actual class with the most probable class of each prediction.
+func (p Predictions) ConfusionMatrix() ConfusionMatrix {
+	m := ConfusionMatrix{
+		counts: make(map[bayes.Class]map[bayes.Class]int),
+	}
+
+	seen := make(map[bayes.Class]bool)
+	for _, pr := range p {
+		actual, predicted := pr.Actual, pr.Predicted()
+		if m.counts[actual] == nil {
+			m.counts[actual] = make(map[bayes.Class]int)
+		}
+		m.counts[actual][predicted]++
+		m.total++
+
+		seen[actual] = true
+		seen[predicted] = true
+	}
+
+	for class := range seen {
+		m.Classes = append(m.Classes, class)
+	}
+	sort.Strings(m.Classes)
+
+	return m
+}
+
+// Count returns number of documents of actual class that were predicted as predicted class.
+func (m ConfusionMatrix) Count(actual, predicted bayes.Class) int {
+	return m.counts[actual][predicted]
+}
+
+// Accuracy returns fraction of documents that were predicted correctly.
+func (m ConfusionMatrix) Accuracy() float64 {
+	correct := 0
+	for _, class := range m.Classes {
+		correct += m.Count(class, class)
+	}
+
+	return ratio(correct, m.total)
+}
+
+// Precision returns fraction of documents predicted as the class that really belong to it.
+// For spam filter it answers: how many messages moved to spam folder are spam?
+func (m ConfusionMatrix) Precision(class bayes.Class) float64 {
+	predicted := 0
+	for _, actual := range m.Classes {
+		predicted += m.Count(actual, class)
+	}
+
+	return ratio(m.Count(class, class), predicted)
+}
+
+// Recall returns fraction of documents of the class that were predicted as the class.
+// For spam filter it answers: how many spam messages were caught?
+func (m ConfusionMatrix) Recall(class bayes.Class) float64 {
+	actual := 0
+	for _, predicted := range m.Classes {
+		actual += m.Count(class, predicted)
+	}
+
+	return ratio(m.Count(class, class), actual)
+}
+
+// F1 returns harmonic mean of precision and recall of the class.
+func (m ConfusionMatrix) F1(class bayes.Class) float64 {
+	precision, recall := m.Precision(class), m.Recall(class)
+	if precision+recall == 0 {
+		return 0
+	}
+
+	return 2 * precision * recall / (precision + recall)
+}
+
+// String renders matrix as a table, with actual classes in rows and predicted classes in columns.
+func (m ConfusionMatrix) String() string {
+	width := len("actual\\predicted")
+	for _, class := range m.Classes {
+		if len(class) > width {
+			width = len(class)
+		}
+	}
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "%-*s", width, "actual\\predicted")
+	for _, class := range m.Classes {
+		fmt.Fprintf(b, " %*s", width, class)
+	}
+	for _, actual := range m.Classes {
+		fmt.Fprintf(b, "\n%-*s", width, actual)
+		for _, predicted := range m.Classes {
+			fmt.Fprintf(b, " %*d", width, m.Count(actual, predicted))
+		}
+	}
+
+	return b.String()
+}
+
+// minProbability clips probabilities in log-loss,
+// because a single confident mistake with probability 0 would make log-loss infinite.
+const minProbability = 1e-15
+
+// LogLoss returns average negative logarithm of probability predicted for actual class.
+// Unlike accuracy it penalises confident mistakes more than uncertain ones,
+// and it's zero only when actual class is always predicted with probability 1.
+func (p Predictions) LogLoss() float64 {
+	if len(p) == 0 {
+		return 0
+	}
+
+	sum := .0
+	for _, pr := range p {
+		sum -= math.Log(math.Max(pr.Posterior[pr.Actual], minProbability))
+	}
+
+	return sum / float64(len(p))
+}
+
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+
+	return float64(a) / float64(b)
+}
@@ -0,0 +1,64 @@
+package evaluation
+
+import (
+	"fmt"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/plotutil"
+)
+
+// ROCPlot renders ROC curve with its area, together with diagonal of classifier that guesses at random.
+func ROCPlot(title string, roc Curve) (*plot.Plot, error) {
+	p, err := newPlot(title, "false positive rate", "true positive rate (recall)")
+	if err != nil {
+		return nil, err
+	}
+
+	err = plotutil.AddLines(p,
+		fmt.Sprintf("ROC (AUC=%.3f)", roc.AUC()), xys(roc),
+		"Random guess", plotter.XYs{{X: 0, Y: 0}, {X: 1, Y: 1}},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// PrecisionRecallPlot renders precision-recall curve.
+func PrecisionRecallPlot(title string, pr Curve) (*plot.Plot, error) {
+	p, err := newPlot(title, "recall", "precision")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := plotutil.AddLines(p, "Precision-recall", xys(pr)); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func newPlot(title, x, y string) (*plot.Plot, error) {
+	p, err := plot.New()
+	if err != nil {
+		return nil, err
+	}
+
+	p.Title.Text = title
+	p.X.Label.Text = x
+	p.Y.Label.Text = y
+	p.X.Min, p.X.Max = 0, 1
+	p.Y.Min, p.Y.Max = 0, 1
+
+	return p, nil
+}
+
+func xys(c Curve) plotter.XYs {
+	result := make(plotter.XYs, len(c))
+	for i, point := range c {
+		result[i] = plotter.XY{X: point.X, Y: point.Y}
+	}
+
+	return result
+}
@@ -0,0 +1,284 @@
+package bayes
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Explanation shows why classifier predicted posterior of a document:
+// how probable was each class before reading the document, and how much each word changed it.
+type Explanation struct {
+	// Prior holds probability of each class before reading the document, P(C),
+	// in complement variant classes are equally probable
+	Prior map[Class]float64
+	// Tokens holds evidence of distinct words, in order of their first occurrence in the document
+	Tokens []Evidence
+	// Absent holds evidence of words of vocabulary that are absent from the document,
+	// sum of log(1 - P(w|C)), only Bernoulli variant uses it
+	Absent map[Class]float64
+	// Scores holds logarithm of unnormalised posterior of each class, sum of prior and evidence,
+	// it gives posterior log odds even when posterior of losing class underflows to zero
+	Scores    map[Class]float64
+	Posterior Posterior
+}
+
+// Evidence holds logarithm of likelihood of all occurrences of a word in each class, as scored by variant of classifier,
+// in multinomial variant it's count * log(P(w|C)).
+type Evidence struct {
+	Token string
+	// Count is how many times word occurred in the document
+	Count int
+	// Ignored is true when word was never seen in training and unknown words are ignored
+	Ignored       bool
+	LogLikelihood map[Class]float64
+}
+
+// LogLikelihoodRatio returns how much all occurrences of the word change log odds of class against other class,
+// in multinomial variant it's
+//
+//	count * log(P(w|class) / P(w|other))
+//
+// Positive value is evidence for class, negative value is evidence for other class.
+func (e Evidence) LogLikelihoodRatio(class, other Class) float64 {
+	if e.Ignored {
+		return 0
+	}
+
+	// word impossible in both classes is no evidence for any of them
+	if math.IsInf(e.LogLikelihood[class], -1) && math.IsInf(e.LogLikelihood[other], -1) {
+		return 0
+	}
+
+	return e.LogLikelihood[class] - e.LogLikelihood[other]
+}
+
+// Explain returns posterior of the document, together with contribution of prior and each word.
+func (c *Classifier) Explain(doc []string) Explanation {
+	e := Explanation{
+		Prior:  make(map[Class]float64),
+		Scores: make(map[Class]float64),
+	}
+	for _, class := range c.Classes() {
+		e.Scores[class] = c.score(class, doc)
+	}
+	e.Posterior = c.posterior(e.Scores)
+
+	for _, class := range c.Classes() {
+		if c.Variant == Complement {
+			e.Prior[class] = 1 / float64(len(c.Classes()))
+		} else {
+			e.Prior[class] = c.Prior(class)
+		}
+	}
+
+	index := make(map[string]int)
+	for _, w := range doc {
+		if i, ok := index[w]; ok {
+			e.Tokens[i].Count++
+			continue
+		}
+
+		index[w] = len(e.Tokens)
+		e.Tokens = append(e.Tokens, Evidence{
+			Token:         w,
+			Count:         1,
+			Ignored:       c.ignored(w),
+			LogLikelihood: make(map[Class]float64),
+		})
+	}
+
+	for i, t := range e.Tokens {
+		if t.Ignored {
+			continue
+		}
+		for _, class := range c.Classes() {
+			e.Tokens[i].LogLikelihood[class] = c.evidence(class, t.Token, t.Count)
+		}
+	}
+
+	if c.Variant == Bernoulli {
+		e.Absent = make(map[Class]float64)
+		for _, class := range c.Classes() {
+			e.Absent[class] = c.absentEvidence(class, doc)
+		}
+	}
+
+	return e
+}
+
+// ExplainText returns explanation of text, that is split into words with Tokenizer.
+func (c *Classifier) ExplainText(text string) Explanation {
+	return c.Explain(c.tokenize(text))
+}
+
+// Contest returns the most probable class, and class that was the most probable alternative,
+// which are compared by renderers. Without alternative, the second class is empty.
+func (e Explanation) Contest() (Class, Class) {
+	best, _ := e.Posterior.Best()
+
+	rest := make(Posterior, len(e.Posterior))
+	for class, p := range e.Posterior {
+		if class != best {
+			rest[class] = p
+		}
+	}
+
+	other, _ := rest.Best()
+
+	return best, other
+}
+
+// PriorLogOdds returns logarithm of prior odds of class against other class, log(P(class)/P(other)).
+func (e Explanation) PriorLogOdds(class, other Class) float64 {
+	return math.Log(e.Prior[class]) - math.Log(e.Prior[other])
+}
+
+// AbsentLogOdds returns how much words absent from the document change log odds of class against other class.
+func (e Explanation) AbsentLogOdds(class, other Class) float64 {
+	if e.Absent == nil {
+		return 0
+	}
+
+	return e.Absent[class] - e.Absent[other]
+}
+
+// PosteriorLogOdds returns logarithm of posterior odds of class against other class,
+// which is sum of prior log odds, log likelihood ratios of all words, and evidence of absent words.
+// When document is impossible in every class, like Predict it falls back to prior log odds.
+func (e Explanation) PosteriorLogOdds(class, other Class) float64 {
+	for _, s := range e.Scores {
+		if !math.IsInf(s, -1) {
+			return e.Scores[class] - e.Scores[other]
+		}
+	}
+
+	return e.PriorLogOdds(class, other)
+}
+
+// line is a row of rendered explanation.
+type line struct {
+	Token   string
+	Count   int
+	Ignored bool
+	Ratio   float64
+}
+
+// lines returns evidence of words ordered from the strongest, in either direction.
+func (e Explanation) lines(class, other Class) []line {
+	result := make([]line, 0, len(e.Tokens))
+	for _, t := range e.Tokens {
+		result = append(result, line{
+			Token:   t.Token,
+			Count:   t.Count,
+			Ignored: t.Ignored,
+			Ratio:   t.LogLikelihoodRatio(class, other),
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return math.Abs(result[i].Ratio) > math.Abs(result[j].Ratio)
+	})
+
+	return result
+}
+
+// WriteText renders explanation as plain text, one word per line, with the strongest evidence first.
+func (e Explanation) WriteText(w io.Writer) error {
+	class, other := e.Contest()
+	b := &strings.Builder{}
+
+	fmt.Fprintf(b, "P(%s|doc)=%.4f", class, e.Posterior[class])
+	if other == "" {
+		fmt.Fprintln(b)
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	fmt.Fprintf(b, " P(%s|doc)=%.4f\n", other, e.Posterior[other])
+	fmt.Fprintf(b, "log odds of %s against %s:\n", class, other)
+	fmt.Fprintf(b, "  %+8.3f  prior P(%s)=%.4f P(%s)=%.4f\n", e.PriorLogOdds(class, other), class, e.Prior[class], other, e.Prior[other])
+	for _, l := range e.lines(class, other) {
+		if l.Ignored {
+			fmt.Fprintf(b, "  %8s  %q unknown, ignored\n", "", l.Token)
+			continue
+		}
+
+		fmt.Fprintf(b, "  %+8.3f  %q x%d\n", l.Ratio, l.Token, l.Count)
+	}
+	if e.Absent != nil {
+		fmt.Fprintf(b, "  %+8.3f  absent words\n", e.AbsentLogOdds(class, other))
+	}
+	fmt.Fprintf(b, "= %+8.3f  posterior\n", e.PosteriorLogOdds(class, other))
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (e Explanation) String() string {
+	b := &strings.Builder{}
+	_ = e.WriteText(b)
+
+	return b.String()
+}
+
+var explanationHTML = template.Must(template.New("explanation").Funcs(template.FuncMap{
+	// formatted number has nothing to escape, but html/template would escape its plus sign
+	"signed": func(x float64) template.HTML {
+		return template.HTML(fmt.Sprintf("%+.3f", x))
+	},
+	"probability": func(x float64) string {
+		return fmt.Sprintf("%.4f", x)
+	},
+}).Parse(`<table class="explanation">
+<caption>P({{.Class}}|doc)={{probability .PClass}}{{if .Other}} P({{.Other}}|doc)={{probability .POther}}{{end}}</caption>
+{{- if .Other}}
+<thead><tr><th>evidence</th><th>count</th><th>log odds of {{.Class}} against {{.Other}}</th></tr></thead>
+<tbody>
+<tr class="prior"><td>prior</td><td></td><td>{{signed .Prior}}</td></tr>
+{{- range .Lines}}
+{{- if .Ignored}}
+<tr class="ignored"><td>{{.Token}}</td><td>{{.Count}}</td><td>unknown, ignored</td></tr>
+{{- else}}
+<tr class="{{if ge .Ratio 0.0}}for{{else}}against{{end}}"><td>{{.Token}}</td><td>{{.Count}}</td><td>{{signed .Ratio}}</td></tr>
+{{- end}}
+{{- end}}
+{{- if .HasAbsent}}
+<tr class="absent"><td>absent words</td><td></td><td>{{signed .Absent}}</td></tr>
+{{- end}}
+</tbody>
+<tfoot><tr><td>posterior</td><td></td><td>{{signed .Posterior}}</td></tr></tfoot>
+{{- end}}
+</table>
+`))
+
+// WriteHTML renders explanation as HTML table, rows of words that are evidence for predicted class
+// have "for" CSS class, and rows of words that are evidence against it have "against" CSS class.
+func (e Explanation) WriteHTML(w io.Writer) error {
+	class, other := e.Contest()
+	data := struct {
+		Class, Other     Class
+		PClass, POther   float64
+		Prior, Posterior float64
+		Lines            []line
+		HasAbsent        bool
+		Absent           float64
+	}{
+		Class:  class,
+		Other:  other,
+		PClass: e.Posterior[class],
+	}
+	if other != "" {
+		data.POther = e.Posterior[other]
+		data.Prior = e.PriorLogOdds(class, other)
+		data.Posterior = e.PosteriorLogOdds(class, other)
+		data.Lines = e.lines(class, other)
+		data.HasAbsent = e.Absent != nil
+		data.Absent = e.AbsentLogOdds(class, other)
+	}
+
+	return explanationHTML.Execute(w, data)
+}
@@ -0,0 +1,101 @@
+package bayes
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestClassifier_Explain(t *testing.T) {
+	c := &Classifier{Alpha: 1}
+	c.Train("spam", []string{"send", "password"})
+	c.Train("spam", []string{"password"})
+	c.Train("ham", []string{"send", "picture"})
+
+	e := c.Explain([]string{"send", "password", "unknown", "password"})
+	assert.Equal(t, c.Predict([]string{"send", "password", "unknown", "password"}), e.Posterior)
+	assert.Equal(t, map[Class]float64{"spam": 2.0 / 3, "ham": 1.0 / 3}, e.Prior)
+
+	assert.Len(t, e.Tokens, 3)
+	assert.Equal(t, "password", e.Tokens[1].Token)
+	assert.Equal(t, 2, e.Tokens[1].Count)
+	assert.True(t, e.Tokens[2].Ignored)
+	assert.Equal(t, 0.0, e.Tokens[2].LogLikelihoodRatio("spam", "ham"))
+
+	// P(password|spam) = 3/6, P(password|ham) = 1/5
+	assert.InDelta(t, 2*math.Log(3.0/6/(1.0/5)), e.Tokens[1].LogLikelihoodRatio("spam", "ham"), 1e-12)
+
+	// prior and evidence of words sum to posterior log odds
+	class, other := e.Contest()
+	assert.Equal(t, Class("spam"), class)
+	assert.Equal(t, Class("ham"), other)
+
+	sum := e.PriorLogOdds(class, other)
+	for _, token := range e.Tokens {
+		sum += token.LogLikelihoodRatio(class, other)
+	}
+	assert.InDelta(t, e.PosteriorLogOdds(class, other), sum, 1e-12)
+}
+
+func TestClassifier_Explain_extremes(t *testing.T) {
+	c := &Classifier{}
+	c.Train("spam", []string{"password", "send"})
+	c.Train("ham", []string{"picture", "send"})
+
+	// posterior of ham underflows to zero, but log odds are still sum of finite evidence
+	c.Alpha = 1
+	doc := make([]string, 2000)
+	for i := range doc {
+		doc[i] = "password"
+	}
+	e := c.Explain(doc)
+	assert.Equal(t, .0, e.Posterior["ham"])
+	assert.InDelta(t, 2000*math.Log(2), e.PosteriorLogOdds("spam", "ham"), 1e-9)
+	assert.Contains(t, e.String(), "+1386.294  posterior")
+
+	// without smoothing document is impossible in every class, and like Predict explanation falls back to prior
+	c.Alpha = 0
+	e = c.Explain([]string{"password", "picture"})
+	assert.Equal(t, Posterior{"spam": 0.5, "ham": 0.5}, e.Posterior)
+	assert.Equal(t, .0, e.PosteriorLogOdds("spam", "ham"))
+	for _, token := range e.Tokens {
+		assert.False(t, math.IsNaN(token.LogLikelihoodRatio("spam", "ham")), token.Token)
+	}
+}
+
+func TestExplanation_WriteText(t *testing.T) {
+	c := &Classifier{Alpha: 1}
+	c.Train("spam", []string{"send", "password"})
+	c.Train("ham", []string{"send", "picture"})
+
+	assert.Equal(t, ""+
+		"P(spam|doc)=0.6667 P(ham|doc)=0.3333\n"+
+		"log odds of spam against ham:\n"+
+		"    +0.000  prior P(spam)=0.5000 P(ham)=0.5000\n"+
+		"    +0.693  \"password\" x1\n"+
+		"    +0.000  \"send\" x1\n"+
+		"            \"how\" unknown, ignored\n"+
+		"=   +0.693  posterior\n", c.Explain([]string{"send", "password", "how"}).String())
+
+	// single class has no alternative to compare with
+	single := &Classifier{}
+	single.Train("spam", []string{"send"})
+	assert.Equal(t, "P(spam|doc)=1.0000\n", single.Explain([]string{"send"}).String())
+}
+
+func TestExplanation_WriteHTML(t *testing.T) {
+	c := &Classifier{Alpha: 1}
+	c.Train("spam", []string{"<script>", "password"})
+	c.Train("ham", []string{"picture"})
+
+	b := &strings.Builder{}
+	assert.NoError(t, c.Explain([]string{"<script>", "picture"}).WriteHTML(b))
+
+	html := b.String()
+	assert.Contains(t, html, `<td>&lt;script&gt;</td>`)
+	assert.NotContains(t, html, `<script>`)
+	assert.Contains(t, html, `<caption>P(ham|doc)=0.6098 P(spam|doc)=0.3902</caption>`)
+	assert.Contains(t, html, `<tr class="for"><td>picture</td><td>1</td><td>+0.916</td></tr>`)
+	assert.Contains(t, html, `<tr class="against"><td>&lt;script&gt;</td><td>1</td><td>-0.470</td></tr>`)
+}
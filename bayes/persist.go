@@ -0,0 +1,385 @@
+package bayes
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"sort"
+)
+
+// modelVersion is version of serialised model, it changes whenever format changes,
+// so that model saved by newer code is rejected instead of silently misread.
+const modelVersion = 2
+
+// multinomialVersion is the first version of model, which has counts of words and documents, but no variant
+// nor numbers of documents that contain each word. It's still loaded, as multinomial classifier,
+// and Bernoulli variant of such classifier learns presence of words only from documents trained after loading.
+const multinomialVersion = 1
+
+// magic starts every model in binary format.
+var magic = [4]byte{'N', 'B', 'A', 'Y'}
+
+var (
+	// ErrVersion is returned when model was saved in version of format that is not supported.
+	ErrVersion = errors.New("bayes: unsupported model version")
+	// ErrCorrupted is returned when model can't be decoded or its values are invalid.
+	ErrCorrupted = errors.New("bayes: corrupted model")
+)
+
+// model is serialised form of Classifier.
+// Tokenizer is code, not data, so it's not saved and must be set again after loading.
+type model struct {
+	Version int          `json:"version"`
+	Alpha   float64      `json:"alpha"`
+	Unknown UnknownWords `json:"unknown"`
+	Variant Variant      `json:"variant"`
+	Classes []classModel `json:"classes"`
+}
+
+type classModel struct {
+	Class Class              `json:"class"`
+	Docs  float64            `json:"docs"`
+	Words map[string]float64 `json:"words"`
+	// Documents holds number of documents of the class that contain the word
+	Documents map[string]float64 `json:"documents"`
+}
+
+func (c *Classifier) model() model {
+	m := model{
+		Version: modelVersion,
+		Alpha:   c.Alpha,
+		Unknown: c.Unknown,
+		Variant: c.Variant,
+		Classes: []classModel{},
+	}
+	for _, class := range c.Classes() {
+		words := make(map[string]float64)
+		for _, w := range c.words.Words(class) {
+			words[w] = c.words.Val(class, w)
+		}
+		documents := make(map[string]float64)
+		for _, w := range c.presence.Words(class) {
+			documents[w] = c.presence.Val(class, w)
+		}
+
+		m.Classes = append(m.Classes, classModel{
+			Class:     class,
+			Docs:      c.words.Docs(class),
+			Words:     words,
+			Documents: documents,
+		})
+	}
+
+	return m
+}
+
+// validate checks that model can be loaded, and that loaded classifier will produce valid probabilities.
+func (m model) validate() error {
+	if err := checkVersion(m.Version); err != nil {
+		return err
+	}
+	if m.Version == multinomialVersion && m.Variant != Multinomial {
+		return fmt.Errorf("%w: variant %d in model version %d", ErrCorrupted, m.Variant, m.Version)
+	}
+	if !isCount(m.Alpha) {
+		return fmt.Errorf("%w: invalid alpha %v", ErrCorrupted, m.Alpha)
+	}
+	if m.Unknown != IgnoreUnknown && m.Unknown != SmoothUnknown {
+		return fmt.Errorf("%w: invalid unknown words policy %d", ErrCorrupted, m.Unknown)
+	}
+	if m.Variant != Multinomial && m.Variant != Bernoulli && m.Variant != Complement {
+		return fmt.Errorf("%w: invalid variant %d", ErrCorrupted, m.Variant)
+	}
+
+	seen := make(map[Class]bool, len(m.Classes))
+	for _, cm := range m.Classes {
+		if seen[cm.Class] {
+			return fmt.Errorf("%w: duplicated class %q", ErrCorrupted, cm.Class)
+		}
+		seen[cm.Class] = true
+
+		if !isCount(cm.Docs) {
+			return fmt.Errorf("%w: invalid number of documents %v of class %q", ErrCorrupted, cm.Docs, cm.Class)
+		}
+		for w, n := range cm.Words {
+			if !isCount(n) {
+				return fmt.Errorf("%w: invalid count %v of word %q in class %q", ErrCorrupted, n, w, cm.Class)
+			}
+		}
+		for w, n := range cm.Documents {
+			if !isCount(n) || n > cm.Docs {
+				return fmt.Errorf("%w: invalid number of documents %v with word %q in class %q", ErrCorrupted, n, w, cm.Class)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkVersion returns error when version of model is not supported.
+func checkVersion(version int) error {
+	if version < multinomialVersion || version > modelVersion {
+		return fmt.Errorf("%w: %d, expected %d to %d", ErrVersion, version, multinomialVersion, modelVersion)
+	}
+
+	return nil
+}
+
+// isCount returns true when x is a finite, non-negative number.
+func isCount(x float64) bool {
+	return x >= 0 && !math.IsInf(x, 1)
+}
+
+// restore replaces learned counts and configuration of classifier with model.
+// Running totals are recomputed from counts, so they are never read from untrusted input.
+func (c *Classifier) restore(m model) error {
+	if err := m.validate(); err != nil {
+		return err
+	}
+
+	var words, presence BowClass
+	for _, cm := range m.Classes {
+		words.AddDocs(cm.Class, cm.Docs)
+		for w, n := range cm.Words {
+			words.Add(cm.Class, w, n)
+		}
+		for w, n := range cm.Documents {
+			presence.Add(cm.Class, w, n)
+		}
+	}
+
+	c.Alpha = m.Alpha
+	c.Unknown = m.Unknown
+	c.Variant = m.Variant
+	c.words = words
+	c.presence = presence
+	c.changed()
+
+	return nil
+}
+
+// MarshalJSON saves counts of words, number of documents of each class, and smoothing configuration.
+func (c *Classifier) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.model())
+}
+
+// UnmarshalJSON loads model saved by MarshalJSON, in current or first version of format.
+// Model of unsupported version or with invalid values is rejected, and classifier is left unchanged.
+func (c *Classifier) UnmarshalJSON(data []byte) error {
+	var m model
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+
+	return c.restore(m)
+}
+
+// MarshalBinary saves the same model as MarshalJSON in compact binary format:
+//
+//	magic "NBAY" | version uint16 | alpha | unknown | variant | classes | CRC32 of all preceding bytes
+//
+// Numbers of elements and lengths of strings are written as uvarints, and counts as float64.
+// Classes and words are sorted, so the same model always gives the same bytes.
+func (c *Classifier) MarshalBinary() ([]byte, error) {
+	m := c.model()
+
+	buf := &bytes.Buffer{}
+	buf.Write(magic[:])
+	writeUint16(buf, uint16(m.Version))
+	writeFloat(buf, m.Alpha)
+	writeUvarint(buf, uint64(m.Unknown))
+	writeUvarint(buf, uint64(m.Variant))
+	writeUvarint(buf, uint64(len(m.Classes)))
+	for _, cm := range m.Classes {
+		writeString(buf, cm.Class)
+		writeFloat(buf, cm.Docs)
+		writeCounts(buf, cm.Words)
+		writeCounts(buf, cm.Documents)
+	}
+
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], crc32.ChecksumIEEE(buf.Bytes()))
+	buf.Write(sum[:])
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary loads model saved by MarshalBinary, in current or first version of format,
+// which has no variant and no numbers of documents that contain each word.
+// Checksum, magic and version are verified before anything is decoded,
+// and classifier is left unchanged when model is rejected.
+func (c *Classifier) UnmarshalBinary(data []byte) error {
+	if len(data) < len(magic)+2+4 {
+		return fmt.Errorf("%w: too short", ErrCorrupted)
+	}
+
+	payload, sum := data[:len(data)-4], binary.BigEndian.Uint32(data[len(data)-4:])
+	if !bytes.Equal(payload[:len(magic)], magic[:]) {
+		return fmt.Errorf("%w: not a model", ErrCorrupted)
+	}
+	if crc32.ChecksumIEEE(payload) != sum {
+		return fmt.Errorf("%w: checksum mismatch", ErrCorrupted)
+	}
+
+	r := &reader{data: payload[len(magic):]}
+	m := model{Version: int(r.uint16())}
+	if r.err == nil {
+		if err := checkVersion(m.Version); err != nil {
+			return err
+		}
+	}
+
+	m.Alpha = r.float()
+	m.Unknown = UnknownWords(r.uvarint())
+	if m.Version > multinomialVersion {
+		m.Variant = Variant(r.uvarint())
+	}
+	classes := r.length()
+	for i := 0; i < classes && r.err == nil; i++ {
+		cm := classModel{
+			Class: r.string(),
+			Docs:  r.float(),
+		}
+		cm.Words = r.counts()
+		if m.Version > multinomialVersion {
+			cm.Documents = r.counts()
+		}
+
+		m.Classes = append(m.Classes, cm)
+	}
+
+	if r.err != nil {
+		return fmt.Errorf("%w: %v", ErrCorrupted, r.err)
+	}
+	if len(r.data) > 0 {
+		return fmt.Errorf("%w: %d unexpected bytes", ErrCorrupted, len(r.data))
+	}
+
+	return c.restore(m)
+}
+
+// writeCounts writes counts of words sorted by words.
+func writeCounts(buf *bytes.Buffer, counts map[string]float64) {
+	words := make([]string, 0, len(counts))
+	for w := range counts {
+		words = append(words, w)
+	}
+	sort.Strings(words)
+
+	writeUvarint(buf, uint64(len(words)))
+	for _, w := range words {
+		writeString(buf, w)
+		writeFloat(buf, counts[w])
+	}
+}
+
+func writeUint16(buf *bytes.Buffer, x uint16) {
+	var b [2]byte
+	binary.BigEndian.PutUint16(b[:], x)
+	buf.Write(b[:])
+}
+
+func writeFloat(buf *bytes.Buffer, x float64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], math.Float64bits(x))
+	buf.Write(b[:])
+}
+
+func writeUvarint(buf *bytes.Buffer, x uint64) {
+	var b [binary.MaxVarintLen64]byte
+	buf.Write(b[:binary.PutUvarint(b[:], x)])
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	writeUvarint(buf, uint64(len(s)))
+	buf.WriteString(s)
+}
+
+// reader decodes binary model, and remembers the first error,
+// so that decoding can be written without checking error after every value.
+type reader struct {
+	data []byte
+	err  error
+}
+
+func (r *reader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n > len(r.data) {
+		r.err = io.ErrUnexpectedEOF
+		return nil
+	}
+
+	b := r.data[:n]
+	r.data = r.data[n:]
+
+	return b
+}
+
+func (r *reader) uint16() uint16 {
+	if b := r.next(2); b != nil {
+		return binary.BigEndian.Uint16(b)
+	}
+
+	return 0
+}
+
+func (r *reader) float() float64 {
+	if b := r.next(8); b != nil {
+		return math.Float64frombits(binary.BigEndian.Uint64(b))
+	}
+
+	return 0
+}
+
+func (r *reader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+
+	x, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.err = errors.New("invalid uvarint")
+		return 0
+	}
+	r.data = r.data[n:]
+
+	return x
+}
+
+// length reads number of elements or bytes, that can't be bigger than remaining data,
+// so that corrupted length never makes decoder allocate or loop more than size of input.
+func (r *reader) length() int {
+	n := r.uvarint()
+	if r.err == nil && n > uint64(len(r.data)) {
+		r.err = io.ErrUnexpectedEOF
+		return 0
+	}
+
+	return int(n)
+}
+
+func (r *reader) string() string {
+	return string(r.next(r.length()))
+}
+
+func (r *reader) counts() map[string]float64 {
+	result := make(map[string]float64)
+	n := r.length()
+	for i := 0; i < n && r.err == nil; i++ {
+		w := r.string()
+		if _, ok := result[w]; ok {
+			r.err = fmt.Errorf("duplicated word %q", w)
+			return nil
+		}
+		result[w] = r.float()
+	}
+
+	return result
+}
@@ -0,0 +1,230 @@
+package bayes
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"hash/crc32"
+	"io/ioutil"
+	"testing"
+)
+
+func trained() *Classifier {
+	c := &Classifier{Alpha: 0.5, Unknown: SmoothUnknown, Variant: Bernoulli}
+	train(c, map[string]Class{
+		"send me your password": "spam",
+		"send me your picture":  "ham",
+		"what is your password": "spam",
+		"what is your name":     "ham",
+		"win money now":         "spam",
+	})
+
+	return c
+}
+
+func TestClassifier_persistence(t *testing.T) {
+	useCases := map[string]struct {
+		marshal   func(c *Classifier) ([]byte, error)
+		unmarshal func(c *Classifier, data []byte) error
+	}{
+		"json": {
+			marshal:   (*Classifier).MarshalJSON,
+			unmarshal: (*Classifier).UnmarshalJSON,
+		},
+		"binary": {
+			marshal:   (*Classifier).MarshalBinary,
+			unmarshal: (*Classifier).UnmarshalBinary,
+		},
+	}
+	for name, uc := range useCases {
+		t.Run(name, func(t *testing.T) {
+			c := trained()
+			data, err := uc.marshal(c)
+			assert.NoError(t, err)
+
+			// the same model always gives the same bytes
+			again, err := uc.marshal(trained())
+			assert.NoError(t, err)
+			assert.Equal(t, data, again)
+
+			loaded := &Classifier{}
+			assert.NoError(t, uc.unmarshal(loaded, data))
+
+			assert.Equal(t, c.Alpha, loaded.Alpha)
+			assert.Equal(t, c.Unknown, loaded.Unknown)
+			assert.Equal(t, c.Variant, loaded.Variant)
+			assert.Equal(t, c.Classes(), loaded.Classes())
+			assert.Equal(t, c.words.Vocabulary(), loaded.words.Vocabulary())
+			for _, class := range c.Classes() {
+				assert.Equal(t, c.Prior(class), loaded.Prior(class))
+				assert.Equal(t, c.words.Total(class), loaded.words.Total(class))
+			}
+
+			doc := []string{"send", "your", "password", "unknown"}
+			assert.Equal(t, c.Predict(doc), loaded.Predict(doc))
+		})
+	}
+}
+
+func TestClassifier_MarshalJSON(t *testing.T) {
+	c := &Classifier{Alpha: 1}
+	c.Train("spam", []string{"send", "password", "password"})
+
+	data, err := json.Marshal(c)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"version": 2,
+		"alpha": 1,
+		"unknown": 0,
+		"variant": 0,
+		"classes": [{"class": "spam", "docs": 1, "words": {"password": 2, "send": 1}, "documents": {"password": 1, "send": 1}}]
+	}`, string(data))
+}
+
+// TestClassifier_Unmarshal_firstVersion loads models saved in the first version of format,
+// which predict the same as when they were saved.
+func TestClassifier_Unmarshal_firstVersion(t *testing.T) {
+	useCases := map[string]func(c *Classifier, data []byte) error{
+		"testdata/model_v1.json": (*Classifier).UnmarshalJSON,
+		"testdata/model_v1.bin":  (*Classifier).UnmarshalBinary,
+	}
+	for file, unmarshal := range useCases {
+		t.Run(file, func(t *testing.T) {
+			data, err := ioutil.ReadFile(file)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			c := &Classifier{Variant: Bernoulli}
+			if !assert.NoError(t, unmarshal(c, data)) {
+				return
+			}
+
+			assert.Equal(t, Multinomial, c.Variant)
+			assert.Equal(t, 1.0, c.Alpha)
+			assert.Equal(t, SmoothUnknown, c.Unknown)
+			assert.Equal(t, 3.0, c.words.TotalDocs())
+			assert.Equal(t, 0, c.presence.Vocabulary())
+
+			p := c.Predict([]string{"send", "password", "unknown"})
+			assert.InDelta(t, 0.7168141592920352, p["spam"], 1e-12)
+
+			// saved again, model is in current version
+			saved, err := c.MarshalJSON()
+			assert.NoError(t, err)
+			assert.Contains(t, string(saved), `"version":2`)
+		})
+	}
+}
+
+func TestClassifier_UnmarshalJSON_rejects(t *testing.T) {
+	useCases := map[string]struct {
+		data     string
+		expected error
+	}{
+		"not json": {
+			data:     `{"version": 2`,
+			expected: ErrCorrupted,
+		},
+		"future version": {
+			data:     `{"version": 3, "alpha": 1, "classes": []}`,
+			expected: ErrVersion,
+		},
+		"variant in first version": {
+			data:     `{"version": 1, "alpha": 1, "variant": 1, "classes": []}`,
+			expected: ErrCorrupted,
+		},
+		"missing version": {
+			data:     `{"alpha": 1, "classes": []}`,
+			expected: ErrVersion,
+		},
+		"negative alpha": {
+			data:     `{"version": 2, "alpha": -1, "classes": []}`,
+			expected: ErrCorrupted,
+		},
+		"unknown words policy": {
+			data:     `{"version": 2, "unknown": 7, "classes": []}`,
+			expected: ErrCorrupted,
+		},
+		"unknown variant": {
+			data:     `{"version": 2, "variant": 7, "classes": []}`,
+			expected: ErrCorrupted,
+		},
+		"more documents with word than documents": {
+			data:     `{"version": 2, "classes": [{"class": "spam", "docs": 1, "words": {"send": 2}, "documents": {"send": 2}}]}`,
+			expected: ErrCorrupted,
+		},
+		"negative count": {
+			data:     `{"version": 2, "classes": [{"class": "spam", "docs": 1, "words": {"send": -1}}]}`,
+			expected: ErrCorrupted,
+		},
+		"duplicated class": {
+			data:     `{"version": 2, "classes": [{"class": "spam", "docs": 1}, {"class": "spam", "docs": 1}]}`,
+			expected: ErrCorrupted,
+		},
+	}
+	for name, uc := range useCases {
+		t.Run(name, func(t *testing.T) {
+			c := trained()
+			err := c.UnmarshalJSON([]byte(uc.data))
+			assert.True(t, errors.Is(err, uc.expected), "unexpected error: %v", err)
+
+			// rejected model leaves classifier unchanged
+			assert.Equal(t, trained().Predict([]string{"password"}), c.Predict([]string{"password"}))
+		})
+	}
+}
+
+func TestClassifier_UnmarshalBinary_rejects(t *testing.T) {
+	data, err := trained().MarshalBinary()
+	assert.NoError(t, err)
+
+	useCases := map[string]struct {
+		data     func() []byte
+		expected error
+	}{
+		"empty": {
+			data:     func() []byte { return nil },
+			expected: ErrCorrupted,
+		},
+		"truncated": {
+			data:     func() []byte { return data[:len(data)/2] },
+			expected: ErrCorrupted,
+		},
+		"flipped bit": {
+			data: func() []byte {
+				result := append([]byte{}, data...)
+				result[len(result)/2] ^= 1
+				return result
+			},
+			expected: ErrCorrupted,
+		},
+		"json given as binary": {
+			data: func() []byte {
+				result, _ := trained().MarshalJSON()
+				return result
+			},
+			expected: ErrCorrupted,
+		},
+		"future version": {
+			data: func() []byte {
+				// valid checksum, but version that this code doesn't know
+				result := append([]byte{}, data[:len(data)-4]...)
+				binary.BigEndian.PutUint16(result[len(magic):], modelVersion+1)
+				var sum [4]byte
+				binary.BigEndian.PutUint32(sum[:], crc32.ChecksumIEEE(result))
+				return append(result, sum[:]...)
+			},
+			expected: ErrVersion,
+		},
+	}
+	for name, uc := range useCases {
+		t.Run(name, func(t *testing.T) {
+			c := trained()
+			err := c.UnmarshalBinary(uc.data())
+			assert.True(t, errors.Is(err, uc.expected), "unexpected error: %v", err)
+			assert.Equal(t, trained().Predict([]string{"password"}), c.Predict([]string{"password"}))
+		})
+	}
+}
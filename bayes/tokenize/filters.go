@@ -0,0 +1,88 @@
+package tokenize
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Placeholders which replace tokens that are too specific to be useful as features.
+// Each URL is different, but presence of URL in message is strong evidence.
+const (
+	URL    = "<url>"
+	Email  = "<email>"
+	Number = "<number>"
+)
+
+var (
+	urlPattern    = regexp.MustCompile(`(?i)^(?:https?://|www\.)`)
+	emailPattern  = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+	numberPattern = regexp.MustCompile(`^\p{N}+(?:[.,]\p{N}+)*$`)
+)
+
+// NormaliseURLs replaces URLs with URL placeholder.
+var NormaliseURLs = replace(urlPattern, URL)
+
+// NormaliseEmails replaces e-mail addresses with Email placeholder.
+var NormaliseEmails = replace(emailPattern, Email)
+
+// NormaliseNumbers replaces numbers with Number placeholder.
+var NormaliseNumbers = replace(numberPattern, Number)
+
+func replace(pattern *regexp.Regexp, placeholder string) Filter {
+	return Map(func(token string) string {
+		if pattern.MatchString(token) {
+			return placeholder
+		}
+
+		return token
+	})
+}
+
+func isPlaceholder(token string) bool {
+	return token == URL || token == Email || token == Number
+}
+
+// StopWords returns filter that removes given words,
+// which are so frequent in every class that they carry no evidence.
+// Filter is case sensitive, so it should be used after Lowercase.
+func StopWords(words ...string) Filter {
+	stop := make(map[string]struct{}, len(words))
+	for _, w := range words {
+		stop[w] = struct{}{}
+	}
+
+	return FilterFunc(func(tokens []string) []string {
+		result := make([]string, 0, len(tokens))
+		for _, t := range tokens {
+			if _, ok := stop[t]; !ok {
+				result = append(result, t)
+			}
+		}
+
+		return result
+	})
+}
+
+// English is a short list of the most frequent English words.
+var English = []string{
+	"a", "an", "and", "are", "as", "at", "be", "but", "by", "for", "from",
+	"if", "in", "into", "is", "it", "of", "on", "or", "so", "such",
+	"that", "the", "their", "then", "there", "these", "they", "this",
+	"to", "was", "were", "will", "with",
+}
+
+// NGrams returns filter that keeps tokens and adds n-grams of consecutive tokens, up to size n.
+// For example with n = 2, "send me money" gives: send, me, money, "send me", "me money".
+// N-grams capture phrases, like "click here", that are stronger evidence than separate words.
+func NGrams(n int) Filter {
+	return FilterFunc(func(tokens []string) []string {
+		result := append([]string{}, tokens...)
+		for size := 2; size <= n; size++ {
+			for i := 0; i+size <= len(tokens); i++ {
+				result = append(result, strings.Join(tokens[i:i+size], " "))
+			}
+		}
+
+		return result
+	})
+}
@@ -0,0 +1,121 @@
+// Package tokenize turns raw text into tokens (features) used by the Bayes classifier.
+//
+// Splitting text on spaces produces different tokens for "Password", "password" and "password!",
+// which spreads evidence of the same word over many features.
+// Tokenization is composed as a Pipeline: segmenter splits text into tokens,
+// and filters transform them - lowercase, normalise URLs, e-mails and numbers,
+// remove stop words, stem, or add n-grams.
+package tokenize
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Tokenizer splits text into tokens.
+type Tokenizer interface {
+	Tokenize(text string) []string
+}
+
+// Func is an adapter that allows use of ordinary function as a Tokenizer.
+type Func func(text string) []string
+
+func (f Func) Tokenize(text string) []string {
+	return f(text)
+}
+
+// Filter transforms list of tokens, it can change, remove or add tokens.
+type Filter interface {
+	Filter(tokens []string) []string
+}
+
+// FilterFunc is an adapter that allows use of ordinary function as a Filter.
+type FilterFunc func(tokens []string) []string
+
+func (f FilterFunc) Filter(tokens []string) []string {
+	return f(tokens)
+}
+
+// Pipeline splits text with Segmenter, and passes tokens through Filters in order.
+type Pipeline struct {
+	// Segmenter splits text into tokens, when nil Alphanumeric is used
+	Segmenter Tokenizer
+	Filters   []Filter
+}
+
+func (p Pipeline) Tokenize(text string) []string {
+	segmenter := p.Segmenter
+	if segmenter == nil {
+		segmenter = Alphanumeric
+	}
+
+	tokens := segmenter.Tokenize(text)
+	for _, f := range p.Filters {
+		tokens = f.Filter(tokens)
+	}
+
+	return tokens
+}
+
+// Default returns pipeline suitable for e-mail messages,
+// that lowercase words, normalise URLs, e-mails and numbers, and removes English stop words.
+func Default() Pipeline {
+	return Pipeline{
+		Segmenter: Alphanumeric,
+		Filters: []Filter{
+			Lowercase,
+			NormaliseURLs,
+			NormaliseEmails,
+			NormaliseNumbers,
+			StopWords(English...),
+		},
+	}
+}
+
+// segments matches, in order of preference:
+// URLs, e-mail addresses, numbers with separators, and words made of Unicode letters, marks and digits,
+// with apostrophes allowed inside of words, like "don't".
+var segments = regexp.MustCompile(`(?i)` +
+	`(?:https?://|www\.)[^\s<>"]+[^\s<>".,;:!?)\]]` +
+	`|[\p{L}\p{N}._%+-]+@[\p{L}\p{N}-]+(?:\.[\p{L}\p{N}-]+)+` +
+	`|\p{N}+(?:[.,]\p{N}+)*` +
+	`|[\p{L}\p{M}\p{N}]+(?:['’][\p{L}\p{M}]+)*`)
+
+// Alphanumeric splits text into runs of letters and digits, URLs, e-mail addresses and numbers.
+// Punctuation and white spaces are dropped.
+// It's not Unicode word segmentation (UAX #29): text of scripts written without spaces,
+// like Chinese or Thai, is one token until the next space or punctuation.
+var Alphanumeric = Func(func(text string) []string {
+	return segments.FindAllString(text, -1)
+})
+
+// Whitespace splits text on white spaces only, like the first spam filter did.
+var Whitespace = Func(strings.Fields)
+
+// Lowercase changes tokens to lower case, in Unicode aware way.
+var Lowercase = Map(strings.ToLower)
+
+// Map returns filter that applies function to each token.
+func Map(fn func(token string) string) Filter {
+	return FilterFunc(func(tokens []string) []string {
+		result := make([]string, len(tokens))
+		for i, t := range tokens {
+			result[i] = fn(t)
+		}
+
+		return result
+	})
+}
+
+// Stem returns filter that replaces tokens with their stems,
+// stemmer is a hook, so any algorithm (Porter, Snowball, ...) can be plugged in.
+// Tokens that were normalised to placeholders are not stemmed.
+func Stem(stemmer func(token string) string) Filter {
+	return Map(func(token string) string {
+		if isPlaceholder(token) {
+			return token
+		}
+
+		return stemmer(token)
+	})
+}
@@ -0,0 +1,68 @@
+package tokenize
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestAlphanumeric(t *testing.T) {
+	useCases := map[string]struct {
+		text     string
+		expected []string
+	}{
+		"punctuation and spaces": {
+			text:     "Send   me your PASSWORD!!! Now, please.",
+			expected: []string{"Send", "me", "your", "PASSWORD", "Now", "please"},
+		},
+		"unicode": {
+			text:     "Zażółć gęślą jaźń — naïve café",
+			expected: []string{"Zażółć", "gęślą", "jaźń", "naïve", "café"},
+		},
+		"script without spaces is not segmented": {
+			text:     "免费赢钱，点击这里",
+			expected: []string{"免费赢钱", "点击这里"},
+		},
+		"apostrophes": {
+			text:     "don't 'quote'",
+			expected: []string{"don't", "quote"},
+		},
+		"urls, emails and numbers": {
+			text:     "Win $1,000.50 at https://example.com/win?id=7. Write to john.doe@example.com!",
+			expected: []string{"Win", "1,000.50", "at", "https://example.com/win?id=7", "Write", "to", "john.doe@example.com"},
+		},
+	}
+	for name, uc := range useCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, uc.expected, Alphanumeric.Tokenize(uc.text))
+		})
+	}
+}
+
+func TestDefault(t *testing.T) {
+	tokens := Default().Tokenize("WIN $1,000 at https://example.com/win or write to John.Doe@Example.com. It is FREE!")
+
+	assert.Equal(t, []string{"win", Number, URL, "write", Email, "free"}, tokens)
+}
+
+func TestPipeline_filters(t *testing.T) {
+	stemmer := func(token string) string {
+		return strings.TrimSuffix(token, "s")
+	}
+
+	p := Pipeline{
+		Segmenter: Whitespace,
+		Filters: []Filter{
+			Lowercase,
+			NormaliseNumbers,
+			Stem(stemmer),
+			NGrams(3),
+		},
+	}
+
+	assert.Equal(t, []string{
+		"send", "me", "password", Number,
+		"send me", "me password", "password " + Number,
+		"send me password", "me password " + Number,
+	}, p.Tokenize("Send me PASSWORDS 42"))
+}
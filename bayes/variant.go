@@ -0,0 +1,259 @@
+package bayes
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// Variant of naive Bayes decides how probability of words in a class is modelled.
+type Variant int
+
+const (
+	// Multinomial models how many times each word occurs in documents of a class.
+	Multinomial Variant = iota
+	// Bernoulli models whether word is present in a document of a class or not,
+	// and takes into account also words of vocabulary that are absent from the document.
+	// Repeating a word doesn't change anything, which suits short messages.
+	Bernoulli
+	// Complement learns how many times each word occurs in documents of all other classes,
+	// and prefers class which complement explains document the worst.
+	// Each class is estimated from documents of the other classes, which are many even when the class is rare,
+	// that's why it suits imbalanced classes. Priors learned from training are not used.
+	Complement
+)
+
+func (v Variant) String() string {
+	switch v {
+	case Multinomial:
+		return "multinomial"
+	case Bernoulli:
+		return "bernoulli"
+	case Complement:
+		return "complement"
+	}
+
+	return "unknown"
+}
+
+// bernoulli returns probability that document of the class contains the word
+//
+//	P(w|C) = (docs(w, C) + α) / (docs(C) + 2α)
+//
+// where docs(w, C) is number of documents of the class that contain the word.
+func (c *Classifier) bernoulli(class Class, w string) float64 {
+	return c.presenceProbability(class, c.presence.Val(class, w))
+}
+
+// presenceProbability returns probability that document of the class contains word,
+// which is contained by given number of documents of the class.
+func (c *Classifier) presenceProbability(class Class, docs float64) float64 {
+	denominator := c.words.Docs(class) + 2*c.Alpha
+	if denominator == 0 {
+		return 0
+	}
+
+	return (docs + c.Alpha) / denominator
+}
+
+// complement returns probability of the word in documents of all classes except the class
+//
+//	P(w|¬C) = (count(w, ¬C) + α) / (total(¬C) + α|V|)
+func (c *Classifier) complement(class Class, w string) float64 {
+	count, total := .0, .0
+	for _, other := range c.words.Classes() {
+		if other != class {
+			count += c.words.Val(other, w)
+			total += c.words.Total(other)
+		}
+	}
+
+	denominator := total + c.Alpha*float64(c.vocabulary())
+	if denominator == 0 {
+		return 0
+	}
+
+	return (count + c.Alpha) / denominator
+}
+
+// logPrior returns logarithm of prior probability of the class used by variant of classifier.
+func (c *Classifier) logPrior(class Class) float64 {
+	if c.Variant == Complement {
+		return -math.Log(float64(len(c.words.Classes())))
+	}
+
+	return math.Log(c.Prior(class))
+}
+
+// evidence returns logarithm of likelihood of count occurrences of the known word in the class.
+// In Bernoulli variant presence of the word counts once, and its absence is part of absent evidence.
+func (c *Classifier) evidence(class Class, w string, count int) float64 {
+	switch c.Variant {
+	case Bernoulli:
+		return math.Log(c.bernoulli(class, w))
+	case Complement:
+		return -float64(count) * math.Log(c.complement(class, w))
+	}
+
+	return float64(count) * math.Log(c.Likelihood(class, w))
+}
+
+// absence holds sum of logarithms of probabilities that words of vocabulary are absent from document of a class,
+// log(1 - P(w|C)) for every w in V. Words present in every document of the class would add log(0),
+// so instead of sum they are counted, and document that doesn't contain all of them is impossible.
+type absence struct {
+	sum     float64
+	certain int
+}
+
+func (a *absence) add(p float64, n int) {
+	if p >= 1 {
+		a.certain += n
+	} else {
+		a.sum += float64(n) * math.Log(1-p)
+	}
+}
+
+// absenceCache holds absence of each class, which is computed in O(|V|),
+// and stays valid until classifier learns, unlearns, decays or its configuration changes.
+type absenceCache struct {
+	mu      sync.Mutex
+	alpha   float64
+	unknown UnknownWords
+	classes map[Class]absence
+}
+
+// changed replaces cache of absences, after counts of classifier changed.
+// Cache is created by methods that change counts, not lazily by predictions,
+// which can run concurrently.
+func (c *Classifier) changed() {
+	c.absences = &absenceCache{}
+}
+
+// absent returns absence of the class, computed again only when classifier changed.
+func (c *Classifier) absent(class Class) absence {
+	cache := c.absences
+	if cache == nil {
+		// classifier that never learned has nothing to cache
+		return c.absentSum(class)
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if cache.classes == nil || cache.alpha != c.Alpha || cache.unknown != c.Unknown {
+		cache.classes = make(map[Class]absence)
+		cache.alpha = c.Alpha
+		cache.unknown = c.Unknown
+	}
+
+	if a, ok := cache.classes[class]; ok {
+		return a
+	}
+
+	a := c.absentSum(class)
+	cache.classes[class] = a
+
+	return a
+}
+
+// absentSum computes absence of the class over whole vocabulary.
+func (c *Classifier) absentSum(class Class) absence {
+
+	// words are sorted, because order of summation changes rounding, and prediction should be reproducible
+	a := absence{}
+	words := c.presence.Words(class)
+	sort.Strings(words)
+	for _, w := range words {
+		a.add(c.bernoulli(class, w), 1)
+	}
+
+	// words of other classes, and unknown word when it's smoothed, are in no document of the class
+	if rest := c.vocabulary() - len(words); rest > 0 {
+		a.add(c.presenceProbability(class, 0), rest)
+	}
+
+	return a
+}
+
+// scoreBernoulli returns logarithm of unnormalised posterior of Bernoulli variant
+//
+//	log(P(C)) + ∑ log(P(w|C)) for w in document + ∑ log(1 - P(w|C)) for w in V, not in document
+//
+// Sum over absent words is cached sum over whole vocabulary, corrected for words present in document,
+// so cost of scoring depends only on length of document.
+func (c *Classifier) scoreBernoulli(class Class, doc []string) float64 {
+	a := c.absent(class)
+	unknown := false
+	for _, w := range distinct(doc) {
+		if c.ignored(w) {
+			continue
+		}
+
+		p := c.bernoulli(class, w)
+		a.sum += math.Log(p)
+		if !c.words.Has(w) {
+			unknown = true
+			continue
+		}
+
+		a.add(p, -1)
+	}
+
+	// all unknown words are one word of vocabulary, so its absence is removed once
+	if unknown {
+		a.add(c.presenceProbability(class, 0), -1)
+	}
+
+	if a.certain > 0 {
+		return math.Inf(-1)
+	}
+
+	return c.logPrior(class) + a.sum
+}
+
+// absentEvidence returns sum of logarithms of probabilities of absence of all words of vocabulary,
+// that are not in the document. Unlike scoreBernoulli it sums words one by one, in O(|V|),
+// so that it can be shown in explanation.
+func (c *Classifier) absentEvidence(class Class, doc []string) float64 {
+	present := make(map[string]bool)
+	unknown := false
+	for _, w := range doc {
+		present[w] = true
+		if !c.words.Has(w) && !c.ignored(w) {
+			unknown = true
+		}
+	}
+
+	vocabulary := make([]string, 0, len(c.words.vocabulary))
+	for w := range c.words.vocabulary {
+		vocabulary = append(vocabulary, w)
+	}
+	sort.Strings(vocabulary)
+
+	result := .0
+	for _, w := range vocabulary {
+		if !present[w] {
+			result += math.Log(1 - c.bernoulli(class, w))
+		}
+	}
+	if c.Unknown == SmoothUnknown && !unknown {
+		result += math.Log(1 - c.presenceProbability(class, 0))
+	}
+
+	return result
+}
+
+// distinct returns words of document without repetitions, in order of their first occurrence.
+func distinct(doc []string) []string {
+	seen := make(map[string]bool, len(doc))
+	result := make([]string, 0, len(doc))
+	for _, w := range doc {
+		if !seen[w] {
+			seen[w] = true
+			result = append(result, w)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,118 @@
+package bayes
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// variants returns classifier of each variant, trained on the same documents
+func variants(alpha float64, unknown UnknownWords) map[Variant]*Classifier {
+	result := make(map[Variant]*Classifier)
+	for _, v := range []Variant{Multinomial, Bernoulli, Complement} {
+		c := &Classifier{Alpha: alpha, Unknown: unknown, Variant: v}
+		c.Train("spam", []string{"send", "password"})
+		c.Train("spam", []string{"password"})
+		c.Train("ham", []string{"send", "picture"})
+		result[v] = c
+	}
+
+	return result
+}
+
+func TestClassifier_Predict_variants(t *testing.T) {
+	c := variants(1, IgnoreUnknown)
+
+	// P(spam) P(password|spam) (1 - P(send|spam)) (1 - P(picture|spam))
+	spam := 2.0 / 3 * 0.75 * 0.5 * 0.75
+	ham := 1.0 / 3 * 1.0 / 3 * 1.0 / 3 * 1.0 / 3
+	assert.InDelta(t, spam/(spam+ham), c[Bernoulli].Predict([]string{"password"})["spam"], 1e-12)
+	assert.Equal(t, 0.75, c[Bernoulli].Likelihood("spam", "password"))
+
+	// repeating a word changes nothing in Bernoulli variant, but strengthens evidence in the others
+	assert.Equal(t, c[Bernoulli].Predict([]string{"password"}), c[Bernoulli].Predict([]string{"password", "password"}))
+	assert.True(t, c[Multinomial].Predict([]string{"password", "password"})["spam"] > c[Multinomial].Predict([]string{"password"})["spam"])
+
+	// P(password|¬spam) = (0 + 1) / (2 + 3), P(password|¬ham) = (2 + 1) / (3 + 3)
+	assert.Equal(t, 0.2, c[Complement].Likelihood("spam", "password"))
+	assert.InDelta(t, 5.0/7, c[Complement].Predict([]string{"password"})["spam"], 1e-12)
+
+	// complement variant doesn't use learned priors
+	assert.Equal(t, Posterior{"spam": 0.5, "ham": 0.5}, c[Complement].Predict([]string{"unknown"}))
+	assert.Equal(t, 2.0/3, c[Multinomial].Predict([]string{"unknown"})["spam"])
+}
+
+func TestClassifier_Predict_bernoulliCache(t *testing.T) {
+	words := []string{"a", "b", "c", "d", "e", "f"}
+	rnd := rand.New(rand.NewSource(0))
+	doc := func() []string {
+		result := make([]string, 1+rnd.Intn(4))
+		for i := range result {
+			result[i] = words[rnd.Intn(len(words))]
+		}
+		return result
+	}
+
+	for _, alpha := range []float64{0, 0.5, 1} {
+		for _, unknown := range []UnknownWords{IgnoreUnknown, SmoothUnknown} {
+			c := &Classifier{Alpha: alpha, Unknown: unknown, Variant: Bernoulli}
+			for i := 0; i < 50; i++ {
+				c.Train([]Class{"x", "y", "z"}[i%3], doc()[1:])
+
+				// cached sum over absent words, scored in O(|doc|), matches sum word by word
+				d := append(doc(), "unknown")
+				expected := make(map[Class]float64)
+				for _, class := range c.Classes() {
+					expected[class] = c.logPrior(class) + c.absentEvidence(class, d)
+					for _, w := range distinct(d) {
+						if !c.ignored(w) {
+							expected[class] += c.evidence(class, w, 1)
+						}
+					}
+				}
+
+				actual := c.Predict(d)
+				for class, p := range c.posterior(expected) {
+					assert.InDelta(t, p, actual[class], 1e-9, "alpha=%v unknown=%v class=%s", alpha, unknown, class)
+				}
+			}
+		}
+	}
+}
+
+func TestClassifier_copy(t *testing.T) {
+	c := variants(1, IgnoreUnknown)[Bernoulli]
+	expected := c.Predict([]string{"password"})
+
+	// copy shares cached absences, which are still valid for its configuration
+	copied := *c
+	assert.Equal(t, expected, copied.Predict([]string{"password"}))
+
+	copied.Alpha = 0.5
+	assert.NotEqual(t, expected, copied.Predict([]string{"password"}))
+	assert.Equal(t, expected, c.Predict([]string{"password"}))
+}
+
+func TestClassifier_Explain_variants(t *testing.T) {
+	for v, c := range variants(1, SmoothUnknown) {
+		t.Run(v.String(), func(t *testing.T) {
+			e := c.Explain([]string{"send", "password", "password", "unknown"})
+
+			class, other := e.Contest()
+			sum := e.PriorLogOdds(class, other) + e.AbsentLogOdds(class, other)
+			for _, token := range e.Tokens {
+				sum += token.LogLikelihoodRatio(class, other)
+			}
+			assert.InDelta(t, e.PosteriorLogOdds(class, other), sum, 1e-12)
+		})
+	}
+}
+
+func TestNormalise_certain(t *testing.T) {
+	assert.Equal(t, Posterior{"a": 0.5, "b": 0.5, "c": 0}, normalise(map[Class]float64{
+		"a": math.Inf(1),
+		"b": math.Inf(1),
+		"c": 0,
+	}))
+}
@@ -0,0 +1,152 @@
+package collision
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func TestSpace_Probability(t *testing.T) {
+	days := NewSpace(365)
+
+	assert.Equal(t, .0, days.Probability(big.NewInt(1)))
+	assert.InDelta(t, 0.507297, days.Probability(big.NewInt(23)), 1e-6)
+	assert.InDelta(t, 0.970374, days.Probability(big.NewInt(50)), 1e-6)
+	assert.Equal(t, 1.0, days.Probability(big.NewInt(366)))
+}
+
+func TestSpace_Probability_approximationContinuity(t *testing.T) {
+	// exact sum and its approximation must agree around the limit
+	s := Bits(40)
+	exact := s.Probability(big.NewInt(exactLimit))
+	approx := s.Probability(big.NewInt(exactLimit + 1))
+	assert.InDelta(t, exact, approx, 1e-6)
+
+	// and close to the size of space
+	s = NewSpace(exactLimit * 2)
+	assert.InDelta(t, 1, s.Probability(big.NewInt(exactLimit*2)), 1e-9)
+}
+
+func TestSpace_ItemsFor(t *testing.T) {
+	useCases := map[string]struct {
+		space    Space
+		p        float64
+		expected string
+	}{
+		"birthday 50%": {
+			space:    NewSpace(365),
+			p:        0.5,
+			expected: "23",
+		},
+		"birthday 99%": {
+			space:    NewSpace(365),
+			p:        0.99,
+			expected: "57",
+		},
+		"32-bit hash 50%": {
+			space:    Bits(32),
+			p:        0.5,
+			expected: "77164",
+		},
+		"64-bit hash 50%": {
+			space:    Bits(64),
+			p:        0.5,
+			expected: "5056937541",
+		},
+	}
+	for name, uc := range useCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, uc.expected, uc.space.ItemsFor(uc.p).String())
+		})
+	}
+}
+
+func TestSpace_ItemsFor_bigSpaces(t *testing.T) {
+	// for 128-bit hash it's ~2.2e19 items, more than fits in int64
+	items := Bits(128).ItemsFor(0.5)
+	f, _ := new(big.Float).SetInt(items).Float64()
+	assert.InEpsilon(t, 2.1719381e19, f, 1e-6)
+	assert.False(t, items.IsInt64())
+
+	items = Bits(256).ItemsFor(1e-18)
+	f, _ = new(big.Float).SetInt(items).Float64()
+	assert.InEpsilon(t, 4.8121e29, f, 1e-4)
+}
+
+func TestSpace_ExpectedPairs(t *testing.T) {
+	assert.InDelta(t, 253.0/365, NewSpace(365).ExpectedPairs(big.NewInt(23)), 1e-12)
+
+	// 2^64 items in 128-bit space, (2^64 choose 2) / 2^128 ≈ 1/2
+	items := new(big.Int).Lsh(big.NewInt(1), 64)
+	assert.InDelta(t, 0.5, Bits(128).ExpectedPairs(items), 1e-12)
+}
+
+func TestSpace_MultiWayProbability(t *testing.T) {
+	days := NewSpace(365)
+
+	// exact value of two way collision of 23 people is 0.507
+	assert.InDelta(t, 0.507, days.MultiWayProbability(big.NewInt(23), 2), 0.02)
+	// three people share birthday, exact value for 88 people is 0.511
+	assert.InDelta(t, 0.511, days.MultiWayProbability(big.NewInt(88), 3), 0.02)
+	// for big spaces approximation agrees with classic one
+	items := Bits(128).ItemsFor(0.5)
+	assert.InDelta(t, 0.5, Bits(128).MultiWayProbability(items, 2), 1e-6)
+	assert.Equal(t, .0, days.MultiWayProbability(big.NewInt(2), 3))
+}
+
+func TestNonUniformProbability(t *testing.T) {
+	uniform := make([]float64, 365)
+	for i := range uniform {
+		uniform[i] = 1.0 / 365
+	}
+	assert.InDelta(t, NewSpace(365).Probability(big.NewInt(23)), NonUniformProbability(uniform, 23), 1e-12)
+
+	// non-uniform buckets make collision more probable
+	skewed := make([]float64, 365)
+	for i := range skewed {
+		skewed[i] = 0.5 / 365
+		if i < 365/2 {
+			skewed[i] = 1.5 / 365
+		}
+	}
+	assert.True(t, NonUniformProbability(skewed, 23) > NonUniformProbability(uniform, 23))
+
+	// two buckets, one of them always chosen
+	assert.Equal(t, 1.0, NonUniformProbability([]float64{1, 0}, 2))
+	assert.Equal(t, 1.0, NonUniformProbability([]float64{0.5, 0.5}, 3))
+}
+
+func TestMultiWayExact(t *testing.T) {
+	// two way coincidence is classic birthday problem
+	for _, k := range []int{2, 10, 23, 50} {
+		assert.InDelta(t, NewSpace(365).Probability(big.NewInt(int64(k))), MultiWayExact(365, k, 2), 1e-9, "k=%d", k)
+	}
+
+	// well known values of 3 and 4-way birthday problem
+	assert.InDelta(t, 0.511, MultiWayExact(365, 88, 3), 1e-3)
+	assert.InDelta(t, 0.503, MultiWayExact(365, 187, 4), 1e-3)
+
+	// 3 items share one of 2 values in 2 of 8 equally probable assignments
+	assert.InDelta(t, 0.25, MultiWayExact(2, 3, 3), 1e-12)
+	// 2 values hold at most 4 items without 3-way collision
+	assert.Equal(t, 1.0, MultiWayExact(2, 5, 3))
+	assert.Equal(t, .0, MultiWayExact(365, 2, 3))
+}
+
+func TestMultiWayExact_approximation(t *testing.T) {
+	days := NewSpace(365)
+	for m := 2; m <= 4; m++ {
+		for _, k := range []int{20, 50, 100, 150} {
+			exact := MultiWayExact(365, k, m)
+			approx := days.MultiWayProbability(big.NewInt(int64(k)), m)
+			assert.InDelta(t, exact, approx, 0.03, "k=%d m=%d", k, m)
+		}
+	}
+}
+
+func TestExpectedSharedValues(t *testing.T) {
+	// for two items, probability that both have given value is 1/n², times n values
+	assert.InDelta(t, 1.0/365, ExpectedSharedValues(365, 2, 2), 1e-12)
+	assert.InDelta(t, 365, ExpectedSharedValues(365, 10, 0), 1e-12)
+	assert.InDelta(t, 0.6671, ExpectedSharedValues(365, 23, 2), 1e-4)
+}
@@ -0,0 +1,66 @@
+package collision
+
+import (
+	"github.com/widmogrod/probability-playground/distributions"
+	"math"
+)
+
+// MultiWayExact returns exact probability that at least m of k items share the same value,
+// when items are drawn uniformly from n values.
+// For m = 2 it's the classic birthday problem.
+//
+// Probability that no value is shared by m items is computed recursively, value after value.
+// Let q(t, j) be probability that j items drawn from t values, never share value m times.
+// When one more value is added, each of j items lands in it with probability 1/(t+1), so
+//
+//	q(t+1, j) = ∑ Binomial(j, 1/(t+1)).PMF(i) * q(t, j-i), for i = 0..m-1
+//
+// All terms are probabilities, so recursion neither overflows nor underflows.
+// Complexity is O(n*k*m), which is fine for spaces like days in a year,
+// for bigger spaces use Space.MultiWayProbability approximation.
+func MultiWayExact(n, k, m int) float64 {
+	if m <= 1 {
+		if k > 0 {
+			return 1
+		}
+		return 0
+	}
+	if k < m {
+		return 0
+	}
+	if k > n*(m-1) {
+		// pigeonhole principle
+		return 1
+	}
+
+	q := make([]float64, k+1)
+	next := make([]float64, k+1)
+	// zero values can hold only zero items
+	q[0] = 1
+	for t := 0; t < n; t++ {
+		for j := 0; j <= k; j++ {
+			b := distributions.Binomial{N: j, P: 1 / float64(t+1)}
+			next[j] = 0
+			for i := 0; i < m && i <= j; i++ {
+				next[j] += b.PMF(i) * q[j-i]
+			}
+		}
+		q, next = next, q
+	}
+
+	return math.Max(0, 1-q[k])
+}
+
+// ExpectedSharedValues returns expected number of values that are shared by at least m of k items,
+// for example expected number of days on which at least m people have birthday.
+//
+// By linearity of expectation it's n times probability that one value is drawn at least m times
+//
+//	E = n * P(X >= m), where X ~ Binomial(k, 1/n)
+func ExpectedSharedValues(n, k, m int) float64 {
+	if m <= 0 {
+		return float64(n)
+	}
+
+	return float64(n) * (1 - distributions.Binomial{N: k, P: 1 / float64(n)}.CDF(m-1))
+}
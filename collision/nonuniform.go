@@ -0,0 +1,35 @@
+package collision
+
+// NonUniformProbability returns probability of at least one collision,
+// when items are drawn from buckets that are not equally probable,
+// like birthdays that are more frequent in some months, or biased hash function.
+//
+// Probability that all items land in different buckets is
+//
+//	P(unique) = k! e_k(p_1, ..., p_n)
+//
+// where e_k is elementary symmetric polynomial of bucket probabilities.
+// It's computed by dynamic programming over scaled terms E_j = j! e_j,
+// which are probabilities themselves, so they don't overflow.
+func NonUniformProbability(buckets []float64, items int) float64 {
+	if items <= 1 {
+		return 0
+	}
+	if items > len(buckets) {
+		return 1
+	}
+
+	unique := make([]float64, items+1)
+	unique[0] = 1
+	for i, p := range buckets {
+		top := i + 1
+		if top > items {
+			top = items
+		}
+		for j := top; j >= 1; j-- {
+			unique[j] += float64(j) * p * unique[j-1]
+		}
+	}
+
+	return 1 - unique[items]
+}
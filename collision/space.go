@@ -0,0 +1,216 @@
+// Package collision answers questions related to the birthday problem,
+// applied to hashes, random identifiers and checksums:
+//
+//   - what is probability of collision, when k items are drawn from space of n values?
+//   - how many items can be drawn, before probability of collision reaches p?
+//   - how many pairs of items are expected to collide?
+//   - what is probability that m items share the same value?
+//
+// Spaces of 128 or 256-bit hashes are bigger than any integer type,
+// and in float64 subtraction like n-k loses all precision,
+// that's why sizes of spaces and numbers of items are represented as big.Int.
+package collision
+
+import (
+	"github.com/widmogrod/probability-playground/distributions"
+	"math"
+	"math/big"
+)
+
+// precision of big.Float computations, enough for spaces of 256-bit hashes
+const precision = 512
+
+// exactLimit is the biggest number of items for which probability is computed
+// by multiplying probabilities of each consecutive item being unique.
+// For more items, sum is approximated with integral (Euler-Maclaurin formula).
+const exactLimit = 1 << 20
+
+// Space of equally probable values, like days in a year or values of a hash function.
+type Space struct {
+	size *big.Int
+}
+
+// NewSpace creates space of n values.
+func NewSpace(n int64) Space {
+	return NewSpaceBig(big.NewInt(n))
+}
+
+// NewSpaceBig creates space of n values, where n can exceed int64.
+func NewSpaceBig(n *big.Int) Space {
+	return Space{size: new(big.Int).Set(n)}
+}
+
+// Bits creates space of values of b-bit hash, which has 2^b values.
+func Bits(b uint) Space {
+	return Space{size: new(big.Int).Lsh(big.NewInt(1), b)}
+}
+
+// Size returns number of values in the space.
+func (s Space) Size() *big.Int {
+	return new(big.Int).Set(s.size)
+}
+
+// ratio returns items/n as float64.
+// Division is done with big.Float, so it's exact enough even when both numbers exceed float64 precision.
+func (s Space) ratio(items *big.Int) float64 {
+	r, _ := new(big.Float).SetPrec(precision).Quo(
+		new(big.Float).SetPrec(precision).SetInt(items),
+		new(big.Float).SetPrec(precision).SetInt(s.size),
+	).Float64()
+
+	return r
+}
+
+func (s Space) float64() float64 {
+	f, _ := new(big.Float).SetInt(s.size).Float64()
+	return f
+}
+
+// Probability of at least one collision, when items are drawn uniformly from the space
+//
+//	P(k) = 1 - n!/((n-k)! n^k) = 1 - ∏(1 - i/n), for i = 1..k-1
+//
+// Logarithm of product is computed, to avoid underflow,
+// and -log(1 - i/n) is accumulated instead of subtracting n-i, which loses precision for big n.
+func (s Space) Probability(items *big.Int) float64 {
+	if items.Cmp(big.NewInt(1)) <= 0 {
+		return 0
+	}
+	if items.Cmp(s.size) > 0 {
+		// pigeonhole principle
+		return 1
+	}
+
+	return -math.Expm1(-s.logUnique(items))
+}
+
+// logUnique returns -log(P(no collision)) = -∑ log(1 - i/n), for i = 0..k-1
+func (s Space) logUnique(items *big.Int) float64 {
+	if items.IsInt64() && items.Int64() <= exactLimit {
+		n := s.float64()
+		k := items.Int64()
+		sum := .0
+		for i := int64(1); i < k; i++ {
+			sum -= math.Log1p(-float64(i) / n)
+		}
+
+		return sum
+	}
+
+	// Euler-Maclaurin formula approximates sum of f(i) = -log(1 - i/n) with integral
+	//
+	//	∑ f(i) ≈ ∫f(t)dt - (f(k) - f(0))/2 + (f'(k) - f'(0))/12
+	//	       = n*h(r) + log(1-r)/2 + r/(12n(1-r))
+	//
+	// where r = k/n and h(r) = (1-r)log(1-r) + r
+	n := s.float64()
+	r := s.ratio(items)
+	if r >= 1 {
+		return math.Inf(1)
+	}
+
+	return n*h(r) + math.Log1p(-r)/2 + r/(12*n*(1-r))
+}
+
+// h(r) = (1-r)log(1-r) + r, for small r it's computed from series
+// r²/2 + r³/6 + r⁴/12 + ... = ∑ r^m/(m(m-1)), to avoid catastrophic cancellation
+func h(r float64) float64 {
+	if r < 1e-3 {
+		result := .0
+		rm := r
+		for m := 2.0; m <= 8; m++ {
+			rm *= r
+			result += rm / (m * (m - 1))
+		}
+
+		return result
+	}
+
+	return (1-r)*math.Log1p(-r) + r
+}
+
+// ItemsFor returns the smallest number of items, for which probability of collision is at least p.
+// For example for 32-bit hash and p = 0.5, it's 77164 items.
+func (s Space) ItemsFor(p float64) *big.Int {
+	switch {
+	case p <= 0:
+		return big.NewInt(0)
+	case p >= 1:
+		return new(big.Int).Add(s.size, big.NewInt(1))
+	}
+
+	// Approximation k ≈ √(2n ln(1/(1-p))) narrows search range,
+	// then binary search finds exact value.
+	approx := math.Sqrt(2 * -math.Log1p(-p))
+	guess := new(big.Float).SetPrec(precision).SetInt(s.size)
+	guess.Sqrt(guess).Mul(guess, big.NewFloat(approx))
+
+	lo, _ := new(big.Float).Quo(guess, big.NewFloat(2)).Int(nil)
+	hi, _ := new(big.Float).Mul(guess, big.NewFloat(2)).Int(nil)
+	hi.Add(hi, big.NewInt(2))
+	if max := new(big.Int).Add(s.size, big.NewInt(1)); hi.Cmp(max) > 0 {
+		hi = max
+	}
+
+	one := big.NewInt(1)
+	for lo.Cmp(hi) < 0 {
+		mid := new(big.Int).Add(lo, hi)
+		mid.Rsh(mid, 1)
+		if s.Probability(mid) >= p {
+			hi = mid
+		} else {
+			lo = mid.Add(mid, one)
+		}
+	}
+
+	return lo
+}
+
+// ExpectedPairs returns expected number of pairs of items that share the same value
+//
+//	E = (k choose 2) / n
+func (s Space) ExpectedPairs(items *big.Int) float64 {
+	k := new(big.Float).SetPrec(precision).SetInt(items)
+	pairs := new(big.Float).SetPrec(precision).Sub(k, big.NewFloat(1))
+	pairs.Mul(pairs, k).Quo(pairs, big.NewFloat(2))
+	pairs.Quo(pairs, new(big.Float).SetPrec(precision).SetInt(s.size))
+
+	result, _ := pairs.Float64()
+	return result
+}
+
+// MultiWayProbability returns probability that at least m items share the same value.
+//
+// Probability is approximated by assuming that counts of items in values are independent,
+// and each of them has Poisson distribution with λ = k/n, then
+//
+//	P ≈ 1 - (1 - P(X >= m))^n
+//
+// Approximation is good when the space has many values,
+// for 88 people and 3-way birthday collision it gives 0.510, where exact value is 0.511.
+// Exact value for small spaces is computed by MultiWayExact.
+func (s Space) MultiWayProbability(items *big.Int, m int) float64 {
+	if m <= 1 {
+		if items.Sign() > 0 {
+			return 1
+		}
+		return 0
+	}
+	if items.Cmp(big.NewInt(int64(m))) < 0 {
+		return 0
+	}
+
+	// Tail P(X >= m) is summed directly, instead of 1 - CDF(m-1),
+	// because for large spaces it's far smaller than float64 precision of 1.
+	bucket := distributions.Poisson{Lambda: s.ratio(items)}
+	tail := .0
+	for j := m; j < m+64; j++ {
+		term := bucket.PMF(j)
+		tail += term
+		if term < tail*1e-17 {
+			break
+		}
+	}
+
+	return -math.Expm1(s.float64() * math.Log1p(-tail))
+}
@@ -0,0 +1,60 @@
+package distributions
+
+import (
+	"gonum.org/v1/gonum/mathext"
+	"math"
+	"math/rand"
+)
+
+// Binomial distribution of number of successes in N independent trials,
+// where each trial has probability of success P.
+type Binomial struct {
+	N int
+	P float64
+}
+
+// PMF returns probability of exactly k successes
+//
+//	f(n,k,p) = (n choose k) p^k (1-p)^(n-k)
+func (b Binomial) PMF(k int) float64 {
+	return math.Exp(b.LogPMF(k))
+}
+
+func (b Binomial) LogPMF(k int) float64 {
+	if k < 0 || k > b.N {
+		return math.Inf(-1)
+	}
+
+	return LogChoose(b.N, k) + xLogY(float64(k), b.P) + xLogY(float64(b.N-k), 1-b.P)
+}
+
+// CDF uses relationship with regularized incomplete beta function
+//
+//	P(X <= k) = I_(1-p)(n-k, k+1)
+func (b Binomial) CDF(k int) float64 {
+	switch {
+	case k < 0:
+		return 0
+	case k >= b.N:
+		return 1
+	}
+
+	return mathext.RegIncBeta(float64(b.N-k), float64(k+1), 1-b.P)
+}
+
+func (b Binomial) Quantile(p float64) int {
+	return quantile(b.CDF, p, 0, b.N)
+}
+
+// Rand draws number of successes using inversion method.
+func (b Binomial) Rand(rnd *rand.Rand) int {
+	return b.Quantile(rnd.Float64())
+}
+
+func (b Binomial) Mean() float64 {
+	return float64(b.N) * b.P
+}
+
+func (b Binomial) Variance() float64 {
+	return float64(b.N) * b.P * (1 - b.P)
+}
@@ -0,0 +1,108 @@
+// Package distributions implements discrete probability distributions
+// that show up when counting outcomes of random experiments.
+//
+// Probabilities are computed in logarithmic space with help of math.Lgamma,
+// in the same way as birthday problem is solved in examples,
+// that's why distributions work for large number of trials,
+// where factorials and binomial coefficients overflow float64.
+package distributions
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Discrete is a probability distribution over integers.
+type Discrete interface {
+	// PMF is probability mass function, probability that random variable is equal to k
+	PMF(k int) float64
+	// LogPMF is natural logarithm of PMF
+	LogPMF(k int) float64
+	// CDF is cumulative distribution function, probability that random variable is not greater than k
+	CDF(k int) float64
+	// Quantile returns the smallest k such that CDF(k) >= p
+	Quantile(p float64) int
+	// Rand draws random value from the distribution
+	Rand(rnd *rand.Rand) int
+	Mean() float64
+	Variance() float64
+}
+
+// maxInt is returned as quantile of unbounded distributions when p = 1,
+// or when CDF never reaches p
+const maxInt = int(^uint(0) >> 1)
+
+// LogChoose returns natural logarithm of binomial coefficient (n choose k)
+//
+//	log(n!/(k!(n-k)!)) = lgamma(n+1) - lgamma(k+1) - lgamma(n-k+1)
+func LogChoose(n, k int) float64 {
+	if k < 0 || k > n {
+		return math.Inf(-1)
+	}
+
+	return lgamma(float64(n+1)) - lgamma(float64(k+1)) - lgamma(float64(n-k+1))
+}
+
+func lgamma(x float64) float64 {
+	res, _ := math.Lgamma(x)
+	return res
+}
+
+// xLogY returns x*log(y), with convention that 0*log(0) = 0.
+// It's needed, because certain event (probability 1) of impossible one (probability 0)
+// otherwise produces NaN instead of logarithm of probability.
+func xLogY(x, y float64) float64 {
+	if x == 0 {
+		return 0
+	}
+
+	return x * math.Log(y)
+}
+
+// quantile finds the smallest k from support [lo, hi] such that cdf(k) >= p.
+// When hi < lo support is unbounded from the top.
+func quantile(cdf func(k int) float64, p float64, lo, hi int) int {
+	unbounded := hi < lo
+	switch {
+	case math.IsNaN(p) || p < 0 || p > 1:
+		panic("distributions: quantile probability out of range [0, 1]")
+	case p == 0:
+		return lo
+	case p == 1 && unbounded:
+		return maxInt
+	case p == 1:
+		return hi
+	}
+
+	if unbounded {
+		// grow upper bound until it covers requested probability,
+		// CDF that never reaches it, like of trials that never succeed, gives maxInt
+		step := 1
+		hi = lo
+		for cdf(hi) < p {
+			if hi == maxInt {
+				return maxInt
+			}
+
+			lo = hi + 1
+			if step > maxInt-hi {
+				hi = maxInt
+			} else {
+				hi += step
+				step *= 2
+			}
+		}
+	}
+
+	// binary search of the smallest k that satisfies cdf(k) >= p
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if cdf(mid) >= p {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+
+	return lo
+}
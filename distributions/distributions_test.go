@@ -0,0 +1,184 @@
+package distributions
+
+import (
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/stat/distuv"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+var discrete = map[string]struct {
+	dist Discrete
+	// upper limit of support that is checked, for unbounded distributions
+	// it's where remaining probability is negligible
+	upper int
+}{
+	"binomial": {
+		dist:  Binomial{N: 20, P: 0.3},
+		upper: 20,
+	},
+	"binomial large n": {
+		dist:  Binomial{N: 5000, P: 0.01},
+		upper: 5000,
+	},
+	"poisson": {
+		dist:  Poisson{Lambda: 4.5},
+		upper: 100,
+	},
+	"geometric": {
+		dist:  Geometric{P: 0.2},
+		upper: 300,
+	},
+	"negative binomial": {
+		dist:  NegativeBinomial{R: 3, P: 0.4},
+		upper: 300,
+	},
+	"hypergeometric": {
+		dist:  Hypergeometric{Population: 50, Successes: 20, Draws: 40},
+		upper: 20,
+	},
+}
+
+func TestDiscrete_PMF(t *testing.T) {
+	for name, uc := range discrete {
+		t.Run(name, func(t *testing.T) {
+			total, mean, second := .0, .0, .0
+			for k := 0; k <= uc.upper; k++ {
+				p := uc.dist.PMF(k)
+				total += p
+				mean += float64(k) * p
+				second += float64(k*k) * p
+
+				assert.InDelta(t, total, uc.dist.CDF(k), 1e-9, "CDF(%d)", k)
+			}
+
+			assert.InDelta(t, 1, total, 1e-9)
+			assert.InDelta(t, uc.dist.Mean(), mean, 1e-6)
+			assert.InDelta(t, uc.dist.Variance(), second-mean*mean, 1e-6)
+			assert.Equal(t, .0, uc.dist.PMF(-1))
+			assert.Equal(t, .0, uc.dist.CDF(-1))
+		})
+	}
+}
+
+func TestDiscrete_Quantile(t *testing.T) {
+	for name, uc := range discrete {
+		t.Run(name, func(t *testing.T) {
+			for k := 0; k <= uc.upper; k++ {
+				if uc.dist.PMF(k) < 1e-12 {
+					continue
+				}
+
+				assert.Equal(t, k, uc.dist.Quantile(uc.dist.CDF(k)), "Quantile(CDF(%d))", k)
+			}
+		})
+	}
+}
+
+func TestDiscrete_Rand(t *testing.T) {
+	for name, uc := range discrete {
+		t.Run(name, func(t *testing.T) {
+			rnd := rand.New(rand.NewSource(1))
+			n := 20000
+			sum := .0
+			for i := 0; i < n; i++ {
+				sum += float64(uc.dist.Rand(rnd))
+			}
+
+			// sample mean should be within few standard errors from the mean
+			stdErr := math.Sqrt(uc.dist.Variance() / float64(n))
+			assert.InDelta(t, uc.dist.Mean(), sum/float64(n), 5*stdErr)
+		})
+	}
+}
+
+func TestBinomial_compareWithGonum(t *testing.T) {
+	b := Binomial{N: 60, P: 0.35}
+	g := distuv.Binomial{N: 60, P: 0.35}
+	for k := 0; k <= 60; k++ {
+		assert.InDelta(t, g.Prob(float64(k)), b.PMF(k), 1e-12)
+		assert.InDelta(t, g.CDF(float64(k)), b.CDF(k), 1e-9)
+	}
+}
+
+func TestPoisson_compareWithGonum(t *testing.T) {
+	p := Poisson{Lambda: 12.5}
+	g := distuv.Poisson{Lambda: 12.5}
+	for k := 0; k <= 60; k++ {
+		assert.InDelta(t, g.Prob(float64(k)), p.PMF(k), 1e-12)
+		assert.InDelta(t, g.CDF(float64(k)), p.CDF(k), 1e-9)
+	}
+}
+
+func TestBinomial_largeN(t *testing.T) {
+	// (10000 choose 5000) overflows float64, but logarithm of it doesn't
+	b := Binomial{N: 10000, P: 0.5}
+	assert.InDelta(t, 0.0079786461, b.PMF(5000), 1e-10)
+	assert.InDelta(t, 0.5039893, b.CDF(5000), 1e-6)
+	assert.Equal(t, 5000, b.Quantile(0.5))
+}
+
+func TestBinomial_certain(t *testing.T) {
+	assert.Equal(t, 1.0, Binomial{N: 10, P: 0}.PMF(0))
+	assert.Equal(t, 1.0, Binomial{N: 10, P: 1}.PMF(10))
+	assert.Equal(t, .0, Binomial{N: 10, P: 1}.PMF(9))
+}
+
+func TestUnbounded_neverSucceeds(t *testing.T) {
+	// trials that never succeed have infinitely many failures, search of quantile must stop
+	rnd := rand.New(rand.NewSource(1))
+	for name, d := range map[string]Discrete{
+		"geometric":         Geometric{P: 0},
+		"negative binomial": NegativeBinomial{R: 3, P: 0},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, maxInt, d.Quantile(0.5))
+			assert.Equal(t, maxInt, d.Rand(rnd))
+			assert.Equal(t, .0, d.CDF(maxInt))
+		})
+	}
+
+	// CDF at the largest int doesn't overflow
+	assert.Equal(t, 1.0, Geometric{P: 0.5}.CDF(maxInt))
+	assert.Equal(t, 1.0, Poisson{Lambda: 3}.CDF(maxInt))
+}
+
+func TestHypergeometric_support(t *testing.T) {
+	// drawing 40 of 50 items, where 30 are failures, must give at least 10 successes
+	h := Hypergeometric{Population: 50, Successes: 20, Draws: 40}
+	assert.Equal(t, .0, h.PMF(9))
+	assert.True(t, h.PMF(10) > 0)
+	assert.Equal(t, 10, h.Quantile(0))
+	assert.Equal(t, 20, h.Quantile(1))
+}
+
+func TestMultinomial(t *testing.T) {
+	// mTurk example: 3 workers, 4 equally probable decisions
+	m := Multinomial{N: 3, P: []float64{0.25, 0.25, 0.25, 0.25}}
+
+	assert.InDelta(t, 1.0/64, m.PMF([]int{3, 0, 0, 0}), 1e-12)
+	assert.InDelta(t, 6.0/64, m.PMF([]int{1, 1, 1, 0}), 1e-12)
+	assert.Equal(t, .0, m.PMF([]int{1, 1, 0, 0}))
+	assert.Equal(t, []float64{0.75, 0.75, 0.75, 0.75}, m.Mean())
+	assert.Equal(t, []float64{0.5625, 0.5625, 0.5625, 0.5625}, m.Variance())
+	assert.Equal(t, -0.1875, m.Covariance(0, 1))
+
+	// probability that at most one worker chose the first decision
+	assert.InDelta(t, 1.0-10.0/64, m.Marginal(0).CDF(1), 1e-12)
+	assert.Equal(t, m.Mean()[0], m.Marginal(0).Mean())
+
+	rnd := rand.New(rand.NewSource(1))
+	m = Multinomial{N: 1000, P: []float64{0.5, 0.3, 0.2}}
+	sums := make([]float64, 3)
+	for i := 0; i < 1000; i++ {
+		counts := m.Rand(rnd)
+		assert.Equal(t, 1000, counts[0]+counts[1]+counts[2])
+		for j, c := range counts {
+			sums[j] += float64(c) / 1000
+		}
+	}
+	for j, mean := range m.Mean() {
+		assert.InDelta(t, mean, sums[j], 5*math.Sqrt(m.Variance()[j]/1000))
+	}
+}
@@ -0,0 +1,55 @@
+package distributions
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Geometric distribution of number of failures before the first success,
+// in independent trials with probability of success P.
+type Geometric struct {
+	P float64
+}
+
+// PMF returns probability of exactly k failures before the first success
+//
+//	f(k) = (1-p)^k p
+func (g Geometric) PMF(k int) float64 {
+	return math.Exp(g.LogPMF(k))
+}
+
+func (g Geometric) LogPMF(k int) float64 {
+	if k < 0 {
+		return math.Inf(-1)
+	}
+
+	return xLogY(float64(k), 1-g.P) + math.Log(g.P)
+}
+
+// CDF is probability that success happens in at most k+1 trials
+//
+//	P(X <= k) = 1 - (1-p)^(k+1)
+func (g Geometric) CDF(k int) float64 {
+	if k < 0 {
+		return 0
+	}
+
+	return -math.Expm1((float64(k) + 1) * math.Log1p(-g.P))
+}
+
+func (g Geometric) Quantile(p float64) int {
+	return quantile(g.CDF, p, 0, -1)
+}
+
+// Rand draws number of failures using inversion method.
+func (g Geometric) Rand(rnd *rand.Rand) int {
+	return g.Quantile(rnd.Float64())
+}
+
+func (g Geometric) Mean() float64 {
+	return (1 - g.P) / g.P
+}
+
+func (g Geometric) Variance() float64 {
+	return (1 - g.P) / (g.P * g.P)
+}
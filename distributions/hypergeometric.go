@@ -0,0 +1,87 @@
+package distributions
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Hypergeometric distribution of number of successes in Draws made without replacement,
+// from Population that contains Successes items considered as success.
+type Hypergeometric struct {
+	Population int
+	Successes  int
+	Draws      int
+}
+
+// PMF returns probability of drawing exactly k successes
+//
+//	f(k) = (K choose k) (N-K choose n-k) / (N choose n)
+func (h Hypergeometric) PMF(k int) float64 {
+	return math.Exp(h.LogPMF(k))
+}
+
+func (h Hypergeometric) LogPMF(k int) float64 {
+	if k < h.min() || k > h.max() {
+		return math.Inf(-1)
+	}
+
+	return LogChoose(h.Successes, k) + LogChoose(h.Population-h.Successes, h.Draws-k) - LogChoose(h.Population, h.Draws)
+}
+
+// CDF sums probabilities of the support, because there is no closed form for it.
+func (h Hypergeometric) CDF(k int) float64 {
+	if k < h.min() {
+		return 0
+	}
+	if k >= h.max() {
+		return 1
+	}
+
+	result := .0
+	for i := h.min(); i <= k; i++ {
+		result += h.PMF(i)
+	}
+
+	return math.Min(1, result)
+}
+
+func (h Hypergeometric) Quantile(p float64) int {
+	return quantile(h.CDF, p, h.min(), h.max())
+}
+
+// Rand draws number of successes using inversion method.
+func (h Hypergeometric) Rand(rnd *rand.Rand) int {
+	return h.Quantile(rnd.Float64())
+}
+
+func (h Hypergeometric) Mean() float64 {
+	return float64(h.Draws) * float64(h.Successes) / float64(h.Population)
+}
+
+func (h Hypergeometric) Variance() float64 {
+	n, k, d := float64(h.Population), float64(h.Successes), float64(h.Draws)
+	if n <= 1 {
+		return 0
+	}
+
+	return d * k / n * (n - k) / n * (n - d) / (n - 1)
+}
+
+// min is the smallest number of successes that can be drawn,
+// when there is not enough failures in population to fill all draws
+func (h Hypergeometric) min() int {
+	if m := h.Draws + h.Successes - h.Population; m > 0 {
+		return m
+	}
+
+	return 0
+}
+
+// max is the biggest number of successes that can be drawn
+func (h Hypergeometric) max() int {
+	if h.Draws < h.Successes {
+		return h.Draws
+	}
+
+	return h.Successes
+}
@@ -0,0 +1,109 @@
+package distributions
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Multinomial distribution of counts of each of len(P) outcomes in N independent trials,
+// where each trial ends with outcome i with probability P[i].
+// It's generalisation of Binomial distribution to more than two outcomes,
+// for example decisions of workers in mTurk example.
+//
+// Multinomial is distribution over vectors of counts, which have no natural order,
+// that's why it doesn't implement Discrete, and has no CDF nor Quantile of its own.
+// CDF and quantile of count of single outcome are provided by its Marginal distribution.
+type Multinomial struct {
+	N int
+	P []float64
+}
+
+// PMF returns probability of observing given counts of outcomes
+//
+//	f(x) = n! / (x1! ... xk!) p1^x1 ... pk^xk
+func (m Multinomial) PMF(counts []int) float64 {
+	return math.Exp(m.LogPMF(counts))
+}
+
+func (m Multinomial) LogPMF(counts []int) float64 {
+	if len(counts) != len(m.P) {
+		return math.Inf(-1)
+	}
+
+	total := 0
+	result := lgamma(float64(m.N + 1))
+	for i, x := range counts {
+		if x < 0 {
+			return math.Inf(-1)
+		}
+
+		total += x
+		result += xLogY(float64(x), m.P[i]) - lgamma(float64(x+1))
+	}
+
+	if total != m.N {
+		return math.Inf(-1)
+	}
+
+	return result
+}
+
+// Rand draws counts of outcomes, as sequence of conditional binomial draws.
+// Count of outcome i is binomial, with trials not assigned to previous outcomes
+// and probability of outcome i relative to probability that remains.
+func (m Multinomial) Rand(rnd *rand.Rand) []int {
+	counts := make([]int, len(m.P))
+	trials := m.N
+	remaining := 1.0
+	for i, p := range m.P {
+		if trials == 0 {
+			break
+		}
+
+		if i == len(m.P)-1 || remaining <= p {
+			counts[i] = trials
+			break
+		}
+
+		counts[i] = Binomial{N: trials, P: p / remaining}.Rand(rnd)
+		trials -= counts[i]
+		remaining -= p
+	}
+
+	return counts
+}
+
+// Marginal returns distribution of count of outcome i alone, which is Binomial{N, P[i]},
+// since each trial either ends with outcome i or not.
+func (m Multinomial) Marginal(i int) Binomial {
+	return Binomial{N: m.N, P: m.P[i]}
+}
+
+// Mean returns expected count of each outcome, N*P[i]
+func (m Multinomial) Mean() []float64 {
+	result := make([]float64, len(m.P))
+	for i, p := range m.P {
+		result[i] = float64(m.N) * p
+	}
+
+	return result
+}
+
+// Variance returns variance of count of each outcome, N*P[i]*(1-P[i])
+func (m Multinomial) Variance() []float64 {
+	result := make([]float64, len(m.P))
+	for i, p := range m.P {
+		result[i] = float64(m.N) * p * (1 - p)
+	}
+
+	return result
+}
+
+// Covariance returns covariance between counts of outcomes i and j
+func (m Multinomial) Covariance(i, j int) float64 {
+	if i == j {
+		return float64(m.N) * m.P[i] * (1 - m.P[i])
+	}
+
+	return -float64(m.N) * m.P[i] * m.P[j]
+}
@@ -0,0 +1,59 @@
+package distributions
+
+import (
+	"gonum.org/v1/gonum/mathext"
+	"math"
+	"math/rand"
+)
+
+// NegativeBinomial distribution of number of failures before R-th success,
+// in independent trials with probability of success P.
+// Geometric distribution is special case where R = 1.
+type NegativeBinomial struct {
+	R float64
+	P float64
+}
+
+// PMF returns probability of exactly k failures before R-th success
+//
+//	f(k) = Γ(k+r) / (k! Γ(r)) p^r (1-p)^k
+func (nb NegativeBinomial) PMF(k int) float64 {
+	return math.Exp(nb.LogPMF(k))
+}
+
+func (nb NegativeBinomial) LogPMF(k int) float64 {
+	if k < 0 {
+		return math.Inf(-1)
+	}
+
+	x := float64(k)
+	return lgamma(x+nb.R) - lgamma(x+1) - lgamma(nb.R) + xLogY(nb.R, nb.P) + xLogY(x, 1-nb.P)
+}
+
+// CDF uses relationship with regularized incomplete beta function
+//
+//	P(X <= k) = I_p(r, k+1)
+func (nb NegativeBinomial) CDF(k int) float64 {
+	if k < 0 {
+		return 0
+	}
+
+	return mathext.RegIncBeta(nb.R, float64(k)+1, nb.P)
+}
+
+func (nb NegativeBinomial) Quantile(p float64) int {
+	return quantile(nb.CDF, p, 0, -1)
+}
+
+// Rand draws number of failures using inversion method.
+func (nb NegativeBinomial) Rand(rnd *rand.Rand) int {
+	return nb.Quantile(rnd.Float64())
+}
+
+func (nb NegativeBinomial) Mean() float64 {
+	return nb.R * (1 - nb.P) / nb.P
+}
+
+func (nb NegativeBinomial) Variance() float64 {
+	return nb.R * (1 - nb.P) / (nb.P * nb.P)
+}
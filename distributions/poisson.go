@@ -0,0 +1,59 @@
+package distributions
+
+import (
+	"gonum.org/v1/gonum/mathext"
+	"math"
+	"math/rand"
+)
+
+// Poisson distribution of number of events that happen in fixed interval,
+// when events occur independently with constant average rate Lambda.
+//
+// It's also a good approximation of Binomial distribution
+// with large N and small P, where Lambda = N*P.
+type Poisson struct {
+	Lambda float64
+}
+
+// PMF returns probability of exactly k events
+//
+//	f(k) = λ^k e^-λ / k!
+func (p Poisson) PMF(k int) float64 {
+	return math.Exp(p.LogPMF(k))
+}
+
+func (p Poisson) LogPMF(k int) float64 {
+	if k < 0 {
+		return math.Inf(-1)
+	}
+
+	return xLogY(float64(k), p.Lambda) - p.Lambda - lgamma(float64(k+1))
+}
+
+// CDF uses relationship with regularized upper incomplete gamma function
+//
+//	P(X <= k) = Q(k+1, λ)
+func (p Poisson) CDF(k int) float64 {
+	if k < 0 {
+		return 0
+	}
+
+	return mathext.GammaIncRegComp(float64(k)+1, p.Lambda)
+}
+
+func (p Poisson) Quantile(q float64) int {
+	return quantile(p.CDF, q, 0, -1)
+}
+
+// Rand draws number of events using inversion method.
+func (p Poisson) Rand(rnd *rand.Rand) int {
+	return p.Quantile(rnd.Float64())
+}
+
+func (p Poisson) Mean() float64 {
+	return p.Lambda
+}
+
+func (p Poisson) Variance() float64 {
+	return p.Lambda
+}
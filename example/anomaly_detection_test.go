@@ -1,12 +1,14 @@
 package example
 
 import (
+	"github.com/widmogrod/probability-playground/anomaly"
+	"github.com/widmogrod/probability-playground/anomaly/synthetic"
 	"gonum.org/v1/plot"
 	"gonum.org/v1/plot/plotter"
 	"gonum.org/v1/plot/plotutil"
 	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
 	"math"
-	"math/rand"
 	"testing"
 )
 
@@ -72,141 +74,143 @@ func TestAnomalyDetection(t *testing.T) {
 	//
 	// Cluster vectors and take a look what you can find...
 
-	rand.Seed(int64(0))
 	p, err := plot.New()
 	if err != nil {
 		panic(err)
 	}
 
+	// behaviour is |sin(0.1t)|, which breaks its regularity during first anomaly,
+	// and jitters during second one
+	series := synthetic.Generator{
+		Components: []synthetic.Component{
+			synthetic.Abs(synthetic.Seasonality(20*math.Pi, 1)),
+		},
+		Anomalies: []synthetic.Injection{
+			{Kind: synthetic.Irregular, At: 51, Duration: 8, Magnitude: 10},
+			{Kind: synthetic.Jitter, At: 81, Duration: 8, Magnitude: 0.5},
+		},
+		N: 150,
+	}.Generate()
+
 	// Let's take a look at rate of change
-	var points, change, change2, change3, average plotter.XYs
-	for i := 0; i < 150; i++ {
-		r := float64(i) * 0.1
+	var (
+		// change is rate of change of behaviour
+		change = anomaly.Diff(1)
+		// change2 is second difference, how fast rate of change changes
+		change2 = anomaly.Pipeline(anomaly.Diff(1), anomaly.Diff(1))
+		// change3 is magnitude of second difference
+		change3 = anomaly.Pipeline(anomaly.Diff(1), anomaly.Diff(1), anomaly.Abs())
+		// average is magnitude of second difference squashed to [0, 1)
+		average = anomaly.Pipeline(anomaly.Diff(1), anomaly.Diff(1), anomaly.Abs(), anomaly.Squash())
+		// detector raises alert when second difference is far from its recent values,
+		// it flags both anomalies, but also cusps of |sin|, where rate of change flips abruptly
+		detector = anomaly.Pipeline(anomaly.Diff(1), anomaly.Diff(1), anomaly.NewZScore(20, 4))
+	)
 
-		if i > 50 && i < 60 {
-			r += rand.Float64()
+	var points, changes, changes2, changes3, averages, alerts plotter.XYs
+	var scores []anomaly.Score
+	observe := func(xys plotter.XYs, s anomaly.Score) plotter.XYs {
+		if !s.Ready {
+			return xys
 		}
 
-		s := math.Abs(math.Sin(r))
-		//s *= math.Pow(s, 5)
-		if i > 80 && i < 90 {
-			s *= rand.Float64() * 3
-		}
+		return append(xys, plotter.XY{X: s.T, Y: s.Value})
+	}
 
+	for _, point := range series.Points {
+		x, s := point.T, point.V
 		points = append(points, plotter.XY{
-			X: float64(i),
+			X: x,
 			Y: s,
 		})
 
-		delta := onWindow(2, points, func(ys ...float64) float64 {
-			return ys[0] - ys[1]
-		})
-
-		prev2 := onWindow(3, points, func(ys ...float64) float64 {
-			return ys[0] - ys[1]
-		})
-
-		change = append(change, plotter.XY{
-			X: float64(i),
-			Y: changeH(points),
-		})
-
-		change2 = append(change2, plotter.XY{
-			X: float64(i),
-			Y: delta - prev2,
-		})
+		changes = observe(changes, change.Observe(x, s))
+		changes2 = observe(changes2, change2.Observe(x, s))
+		changes3 = observe(changes3, change3.Observe(x, s))
 
-		change3 = append(change3, plotter.XY{
-			X: float64(i),
-			Y: math.Sqrt(math.Pow(delta-prev2, 2)),
-		})
+		score := average.Observe(x, s)
+		if x >= 10 {
+			averages = observe(averages, score)
+		}
 
-		if i >= 10 {
-			average = append(average, plotter.XY{
-				X: float64(i),
-				Y: normalise(change3),
-			})
+		score = detector.Observe(x, s)
+		scores = append(scores, score)
+		if score.Alert {
+			alerts = append(alerts, plotter.XY{X: x, Y: s})
 		}
 	}
 
-	err = plotutil.AddLinePoints(p,
-		"behaviour", points,
-		//"change(1)", change,
-		//"change(2)", change2,
-		//"√(𝚫₁^2+𝚫₂^2)", change3,
-		"average", average,
-	)
-	if err != nil {
-		t.Fatal(err)
+	e := anomaly.Evaluate(scores, series.Anomalies, 2)
+	t.Logf("alerts at: %v", alerts)
+	t.Logf("precision=%.2f recall=%.2f", e.Precision(), e.Recall())
+	if e.Recall() == 0 {
+		t.Fatal("expected alerts at anomalies")
 	}
 
-	if err := p.Save(18*vg.Inch, 9*vg.Inch, "anomaly_detection_test.png"); err != nil {
-		t.Fatal(err)
+	// P(Anomaly | Evidence) of each bucket of time, where evidence is number of alerts in bucket,
+	// and average magnitude of second difference, which is small for smooth behaviour
+	posterior := anomaly.Posterior{
+		Prior:     0.1,
+		Threshold: 0.5,
+		Features: []anomaly.Feature{
+			{Name: "alerts", Normal: anomaly.Poisson(0.5), Anomalous: anomaly.Poisson(2)},
+			{Name: "average", Normal: anomaly.Normal(0.02, 0.05), Anomalous: anomaly.Uniform(0, 1)},
+		},
 	}
-}
 
-func avgH(windowSize int, xys plotter.XYs) float64 {
-	i := len(xys) - 1
-	avg := .0
-	if i >= windowSize {
-		agg := .0
-		for j := 0; j < windowSize; j++ {
-			agg += xys[i-j].Y
-		}
-		avg = agg / float64(windowSize)
+	var averagePoints, alertPoints []anomaly.Point
+	for _, xy := range averages {
+		averagePoints = append(averagePoints, anomaly.Point{T: xy.X, V: xy.Y})
+	}
+	for _, xy := range alerts {
+		alertPoints = append(alertPoints, anomaly.Point{T: xy.X, V: 1})
 	}
 
-	return avg
-}
+	alertBuckets := anomaly.Buckets(alertPoints, 10)
+	var buckets []anomaly.Score
+	for _, b := range anomaly.Buckets(averagePoints, 10) {
+		evidence := anomaly.Evidence{"average": b.Mean(), "alerts": 0}
+		for _, a := range alertBuckets {
+			if a.T == b.T {
+				evidence["alerts"] = a.Count()
+			}
+		}
 
-func sumH(windowSize int, xys plotter.XYs) float64 {
-	i := len(xys) - 1
-	sum := .0
-	if i >= windowSize {
-		for j := 0; j < windowSize; j++ {
-			sum += xys[i-j].Y
+		// bucket is scored at its end, when all its evidence is known
+		score := posterior.Observe(b.T+10, evidence)
+		buckets = append(buckets, score)
+		if score.Alert {
+			t.Logf("bucket [%v, %v) %s", b.T, b.T+10, posterior.Explain(evidence))
 		}
 	}
 
-	return sum
-}
-
-func changeH(xys plotter.XYs) float64 {
-	i := len(xys) - 1
-	diff := .0
-	if i > 1 {
-		diff = xys[i].Y - xys[i-1].Y
+	e = anomaly.Evaluate(buckets, series.Anomalies, 10)
+	t.Logf("buckets precision=%.2f recall=%.2f", e.Precision(), e.Recall())
+	if e.Recall() < 1 {
+		t.Fatal("expected anomalous buckets at anomalies")
 	}
 
-	return diff
-}
-
-func onWindow(windowSize int, xys plotter.XYs, fn func(ys ...float64) float64) float64 {
-	i := len(xys)
-	if i < windowSize {
-		return 0
+	err = plotutil.AddLinePoints(p,
+		"behaviour", points,
+		//"change(1)", changes,
+		//"change(2)", changes2,
+		//"|𝚫₂|", changes3,
+		"average", averages,
+	)
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	attr := make([]float64, windowSize+1)
-	for j := windowSize; j > 0; j-- {
-		attr[windowSize-j] = xys[i-j].Y
+	scatter, err := plotter.NewScatter(alerts)
+	if err != nil {
+		t.Fatal(err)
 	}
-	return fn(attr...)
-}
-
-func normalise(xys plotter.XYs) float64 {
-	return onWindow(1, xys, func(ys ...float64) float64 {
-		return 1 - (1 / (1 + ys[0]))
-	})
-}
+	scatter.GlyphStyle.Shape = draw.CrossGlyph{}
+	scatter.GlyphStyle.Radius = vg.Points(6)
+	p.Add(scatter)
+	p.Legend.Add("alert", scatter)
 
-func expAvg(windowSize int, xys plotter.XYs) float64 {
-	return onWindow(windowSize, xys, func(ys ...float64) float64 {
-		var res float64
-		for _, y := range ys {
-			res += (1 - (1 / (1 + y)))
-		}
-
-		return res / float64(windowSize)
-	})
+	if err := p.Save(18*vg.Inch, 9*vg.Inch, "anomaly_detection_test.png"); err != nil {
+		t.Fatal(err)
+	}
 }
@@ -1,6 +1,8 @@
 package example
 
 import (
+	"github.com/widmogrod/probability-playground/montecarlo"
+	"github.com/widmogrod/probability-playground/probtest"
 	"golang.org/x/exp/errors/fmt"
 	"gonum.org/v1/plot"
 	"gonum.org/v1/plot/plotter"
@@ -28,51 +30,61 @@ import (
 func TestAWSMechanicalTurkProbabilityOfConsensusMonteCarlo(t *testing.T) {
 	n := 10000
 	k := 3
-	epsilon := 0.01
+	seed := int64(0)
 
-	consensusProbability := mTurkMonteCarlo(n, k)
+	consensusProbability := mTurkMonteCarlo(seed, n, k)
+	t.Logf("simulation seed=%d", seed)
+	for degree := 1; degree <= k; degree++ {
+		t.Logf("consensus of degree %d: %s", degree, consensusProbability[degree])
+	}
+
+	// With four decisions and three workers there are 4^3 = 64 equally probable answers:
+	// - 4 of them where all workers agree,
+	// - 4*3*2 = 24 where each worker makes different decision,
+	// - rest 64-4-24 = 36 where exactly two workers agree.
+	expected := []float64{0, 24.0 / 64, 36.0 / 64, 4.0 / 64}
 
-	between(t, consensusProbability[3], 0.06, 0.08, epsilon)
-	between(t, consensusProbability[2], 0.53, 0.55, epsilon)
-	between(t, consensusProbability[1], 0.35, 0.38, epsilon)
+	s := probtest.NewSuite(t, probtest.DefaultFalseAlarmRate, k)
+	for degree := 1; degree <= k; degree++ {
+		e := consensusProbability[degree]
+		s.AssertProportion(e.Successes, e.Trials, expected[degree])
+	}
 }
 
 // mTurkMonteCarlo simulation where
 // - n-tasks to be solved by workers
 // - each tasks has to be answer k-times
-func mTurkMonteCarlo(n, k int) []float64 {
-	consensusDegrees := make([]float64, k+1)
-	consensusProbability := make([]float64, k+1)
-
-	// there is n tasks to be solved by workers,
-	for i := 0; i < n; i++ {
-		votes := make([]float64, 4)
-		// each task must be answer k-times
-		for w := 0; w < k; w++ {
-			decision := rand.Float32()
-			if decision <= 0.25 {
-				votes[0]++
-			} else if decision <= 0.5 {
-				votes[1]++
-			} else if decision <= 0.75 {
-				votes[2]++
-			} else {
-				votes[3]++
-			}
-		}
-
-		consensusReached := int(max(votes))
-		consensusDegrees[consensusReached]++
-	}
-
+// - seed initialises random source, the same seed always reproduces the same result
+func mTurkMonteCarlo(seed int64, n, k int) []montecarlo.Estimate {
 	// What is probability of degree 3,2 and 1 consensus?
 	// or in other words:
 	// What is probability that in n-tasks workers making decision on random, will reach consensus on the same decision?
-	for degree, reachTimes := range consensusDegrees {
-		consensusProbability[degree] = reachTimes / float64(n)
+	return montecarlo.Run(n, seed, mTurkExperiment{k: k}).Estimates(k + 1)
+}
+
+// mTurkExperiment is a single task that has to be answer k-times,
+// outcome of a trial is degree of consensus reached on the task.
+type mTurkExperiment struct {
+	k int
+}
+
+func (e mTurkExperiment) Trial(rnd *rand.Rand) montecarlo.Outcome {
+	votes := make([]float64, 4)
+	// each task must be answer k-times
+	for w := 0; w < e.k; w++ {
+		decision := rnd.Float32()
+		if decision <= 0.25 {
+			votes[0]++
+		} else if decision <= 0.5 {
+			votes[1]++
+		} else if decision <= 0.75 {
+			votes[2]++
+		} else {
+			votes[3]++
+		}
 	}
 
-	return consensusProbability
+	return int(max(votes))
 }
 
 func max(xs []float64) float64 {
@@ -87,14 +99,15 @@ func max(xs []float64) float64 {
 }
 
 func TestPlotDistributionOfAWSMechanicalTurkProbabilityOfConsensusMonteCarlo(t *testing.T) {
-	rand.Seed(int64(0))
+	// each number of workers is simulated with its own seed = seed + workers
+	seed := int64(0)
 
 	p, err := plot.New()
 	if err != nil {
 		panic(err)
 	}
 
-	p.Title.Text = "mTurn degrees of consensus"
+	p.Title.Text = fmt.Sprintf("mTurn degrees of consensus (seed=%d+workers)", seed)
 	p.X.Label.Text = "decisions per task"
 	p.Y.Label.Text = "probability of degree of consensus"
 	p.Legend.Top = true
@@ -109,12 +122,12 @@ func TestPlotDistributionOfAWSMechanicalTurkProbabilityOfConsensusMonteCarlo(t *
 	}
 
 	for workers := 0; workers < maxWorkers; workers++ {
-		consensusProbability := mTurkMonteCarlo(n, workers)
+		consensusProbability := mTurkMonteCarlo(seed+int64(workers), n, workers)
 		for degree, probability := range consensusProbability {
 			if d, ok := degrees[degree*2+1].(plotter.XYs); ok {
 				degrees[degree*2+1] = append(
 					d,
-					plotter.XY{float64(workers), probability},
+					plotter.XY{X: float64(workers), Y: probability.Value},
 				)
 			}
 		}
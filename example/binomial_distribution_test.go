@@ -2,24 +2,15 @@ package example
 
 import (
 	"fmt"
-	"gonum.org/v1/gonum/stat/combin"
+	"github.com/widmogrod/probability-playground/distributions"
 	"gonum.org/v1/plot"
 	"gonum.org/v1/plot/plotter"
 	"gonum.org/v1/plot/plotutil"
 	"gonum.org/v1/plot/vg"
-	"math"
-	"math/rand"
 	"testing"
 )
 
-func binomialDistribution(n, k int, p float64) float64 {
-	// f(n,k,p) = (n choose k) p^k (1-p)^(n-k)
-	return float64(combin.Binomial(n, k)) * math.Pow(p, float64(k)) * math.Pow(1.0-p, float64(n-k))
-}
-
 func TestPlotBinomialDistribution(t *testing.T) {
-	rand.Seed(int64(0))
-
 	p, err := plot.New()
 	if err != nil {
 		panic(err)
@@ -37,7 +28,7 @@ func TestPlotBinomialDistribution(t *testing.T) {
 		for n := 1; n <= 30; n++ {
 			points = append(points, plotter.XY{
 				X: float64(n),
-				Y: binomialDistribution(n, n, p),
+				Y: distributions.Binomial{N: n, P: p}.PMF(n),
 			})
 		}
 
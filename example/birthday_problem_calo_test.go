@@ -2,11 +2,15 @@ package example
 
 import (
 	"fmt"
+	"github.com/widmogrod/probability-playground/collision"
+	"github.com/widmogrod/probability-playground/montecarlo"
+	"github.com/widmogrod/probability-playground/probtest"
 	"gonum.org/v1/plot"
 	"gonum.org/v1/plot/plotter"
 	"gonum.org/v1/plot/plotutil"
 	"gonum.org/v1/plot/vg"
 	"math"
+	"math/big"
 	"math/rand"
 	"testing"
 )
@@ -23,27 +27,59 @@ import (
 // birthday paradox help to answer question what is probability of random hash collision.
 // Important to have in back of your mind when building systems that are based on checksums, and randomly generated hashes.
 // In short, such collision is more probable that you can think of ;)
-func birthdayProblemMonteCarlo(samples, n, k int) float64 {
-	success := .0
-	// for each samples group of k participants
-	for i := 0; i < samples; i++ {
-		peopleWithBirthday := make([]bool, n)
-		// ask each participant
-		for w := 0; w < k; w++ {
-			// for birth date
-			day := rand.Intn(n)
-			// and when at least two share the same date
-			if peopleWithBirthday[day] {
-				// count group as successful example
-				success++
-				break
-			} else {
-				peopleWithBirthday[day] = true
-			}
+//
+// Seed initialises random source, the same seed always reproduces the same result.
+func birthdayProblemMonteCarlo(seed int64, samples, n, k int) montecarlo.Estimate {
+	return montecarlo.Run(samples, seed, birthdayExperiment{n: n, k: k}).Estimate(montecarlo.Success)
+}
+
+// birthdayExperiment is a single trial of birthday problem,
+// where group of k participants is asked about birth date
+// that is one of n possible days.
+type birthdayExperiment struct {
+	n, k int
+}
+
+func (e birthdayExperiment) Trial(rnd *rand.Rand) montecarlo.Outcome {
+	peopleWithBirthday := make([]bool, e.n)
+	// ask each participant
+	for w := 0; w < e.k; w++ {
+		// for birth date
+		day := rnd.Intn(e.n)
+		// and when at least two share the same date
+		if peopleWithBirthday[day] {
+			// count group as successful example
+			return montecarlo.Success
 		}
+
+		peopleWithBirthday[day] = true
 	}
 
-	return success / float64(samples)
+	return montecarlo.Failure
+}
+
+// birthdayCountingExperiment generalises birthday problem to question:
+//
+// > How probable is that at least m-people share the same birthday?
+//
+// Instead of stopping at the first pair, it counts how many people have birthday on each day,
+// and outcome of a trial is number of days shared by at least m people.
+type birthdayCountingExperiment struct {
+	n, k, m int
+}
+
+func (e birthdayCountingExperiment) Trial(rnd *rand.Rand) montecarlo.Outcome {
+	peopleWithBirthday := make([]int, e.n)
+	shared := 0
+	for w := 0; w < e.k; w++ {
+		day := rnd.Intn(e.n)
+		peopleWithBirthday[day]++
+		if peopleWithBirthday[day] == e.m {
+			shared++
+		}
+	}
+
+	return shared
 }
 
 // Theoretical probability calculated following complement rule.
@@ -108,13 +144,47 @@ func birthdayProblemTheoretical2(n, k float64) float64 {
 }
 
 func TestBirthdayProblemMonteCarlo(t *testing.T) {
-	simulation := birthdayProblemMonteCarlo(1000, 365, 23)
+	seed := int64(0)
+	simulation := birthdayProblemMonteCarlo(seed, 1000, 365, 23)
+	t.Logf("simulation seed=%d p=%s", seed, simulation)
 	theoretical := birthdayProblemTheoretical(365, 23)
 	theoretical2 := birthdayProblemTheoretical2(365, 23)
 
-	between(t, simulation, 0.48, 0.51, 0.01)
-	between(t, theoretical, 0.48, 0.51, 0.01)
-	between(t, theoretical2, 0.48, 0.51, 0.01)
+	// Both methods must agree with well known answer for 23 people
+	probtest.AssertClose(t, 0.507297, theoretical, 0, 0.000001)
+	probtest.AssertClose(t, theoretical, theoretical2, 1e-9, 0)
+	probtest.AssertClose(t, theoretical, collision.NewSpace(365).Probability(big.NewInt(23)), 1e-9, 0)
+	// and simulation should be consistent with theory
+	probtest.AssertProportion(t, simulation.Successes, simulation.Trials, theoretical, probtest.DefaultFalseAlarmRate)
+}
+
+func TestMultiWayBirthdayProblemMonteCarlo(t *testing.T) {
+	useCases := map[string]struct {
+		k, m int
+	}{
+		"2 people, 23 in group":  {k: 23, m: 2},
+		"3 people, 88 in group":  {k: 88, m: 3},
+		"4 people, 187 in group": {k: 187, m: 4},
+	}
+	for name, uc := range useCases {
+		t.Run(name, func(t *testing.T) {
+			days := 365
+			result := montecarlo.Run(20000, 0, birthdayCountingExperiment{n: days, k: uc.k, m: uc.m})
+
+			exact := collision.MultiWayExact(days, uc.k, uc.m)
+			approx := collision.NewSpace(int64(days)).MultiWayProbability(big.NewInt(int64(uc.k)), uc.m)
+			expectedDays := collision.ExpectedSharedValues(days, uc.k, uc.m)
+			t.Logf("exact=%f poisson=%f simulation=%s", exact, approx, montecarlo.NewEstimate(result.Trials-result.Count(0), result.Trials))
+			t.Logf("expected shared days=%f simulation=%f", expectedDays, result.Mean())
+
+			// probability that at least one day is shared, is complement of no shared days
+			probtest.AssertProportion(t, result.Trials-result.Count(0), result.Trials, exact, probtest.DefaultFalseAlarmRate)
+			probtest.AssertClose(t, exact, approx, 0, 0.02)
+			// sample mean should be within few standard errors from expected value
+			stdErr := math.Sqrt(result.Variance() / float64(result.Trials))
+			probtest.AssertClose(t, expectedDays, result.Mean(), 0, 5*stdErr)
+		})
+	}
 }
 
 func TestBirthdayProblemPlot(t *testing.T) {
@@ -128,13 +198,26 @@ func TestBirthdayProblemPlot(t *testing.T) {
 	p.Y.Label.Text = "p(k) probability"
 
 	days := 365
-	sampleSize := 300
+	// each point of simulation is run until its estimate is known with given precision
+	precision := 0.03
+	// each point of simulation is run with its own seed = seed + k
+	seed := int64(0)
 
 	var resultMC, resultT1 plotter.XYs
 	for i := 0; i < 100; i++ {
+		result := montecarlo.AdaptiveRunner{
+			MaxTrials: 10000,
+			BatchSize: 100,
+			Seed:      seed + int64(i),
+			Precision: montecarlo.Precision{
+				Outcome:   montecarlo.Success,
+				HalfWidth: precision,
+			},
+		}.Run(birthdayExperiment{n: days, k: i})
+
 		resultMC = append(resultMC, plotter.XY{
 			X: float64(i),
-			Y: birthdayProblemMonteCarlo(sampleSize, days, i),
+			Y: result.Estimate(montecarlo.Success).Value,
 		})
 		resultT1 = append(resultT1, plotter.XY{
 			X: float64(i),
@@ -143,7 +226,7 @@ func TestBirthdayProblemPlot(t *testing.T) {
 	}
 
 	err = plotutil.AddLinePoints(p,
-		fmt.Sprintf("Simulation (sample=%d) ", sampleSize), resultMC,
+		fmt.Sprintf("Simulation (±%.2f, seed=%d+k) ", precision, seed), resultMC,
 		"Theoretical", resultT1,
 	)
 	if err != nil {
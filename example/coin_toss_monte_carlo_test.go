@@ -1,25 +1,49 @@
 package example
 
 import (
+	"github.com/widmogrod/probability-playground/montecarlo"
+	"github.com/widmogrod/probability-playground/probtest"
 	"math/rand"
 	"testing"
 )
 
+const (
+	heads montecarlo.Outcome = iota
+	tails
+)
+
+// coinTossExperiment is a single toss of a fair coin
+var coinTossExperiment = montecarlo.ExperimentFunc(func(rnd *rand.Rand) montecarlo.Outcome {
+	if rnd.Float32() <= 0.5 {
+		return heads
+	}
+
+	return tails
+})
+
 func TestCoinTossMonteCarlo(t *testing.T) {
-	n := 1000
-	epsilon := 0.05
-	heads, tails := .0, .0
-	for i := 0; i < n; i++ {
-		if rand.Float32() <= 0.5 {
-			heads++
-		} else {
-			tails++
-		}
+	// Toss until probability of heads is known with 5% relative error
+	runner := montecarlo.AdaptiveRunner{
+		MaxTrials: 100000,
+		BatchSize: 100,
+		Seed:      0,
+		Precision: montecarlo.Precision{
+			Outcome:       heads,
+			RelativeError: 0.05,
+		},
+	}
+
+	result := runner.Run(coinTossExperiment)
+	t.Logf("simulation seed=%d trials=%d", result.Seed, result.Trials)
+	if !result.Reached {
+		t.Errorf("precision not reached within %d trials", runner.MaxTrials)
 	}
 
-	headProbability := heads / float64(n)
-	tailProbability := tails / float64(n)
+	headProbability := result.Estimate(heads)
+	tailProbability := result.Estimate(tails)
+	t.Logf("P(heads)=%s P(tails)=%s", headProbability, tailProbability)
 
-	between(t, headProbability, 0, 0.5, epsilon)
-	between(t, tailProbability, 0, 0.5, epsilon)
+	s := probtest.NewSuite(t, probtest.DefaultFalseAlarmRate, 2)
+	s.AssertProportion(headProbability.Successes, headProbability.Trials, 0.5)
+	s.AssertProportion(tailProbability.Successes, tailProbability.Trials, 0.5)
 }
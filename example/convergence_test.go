@@ -0,0 +1,72 @@
+package example
+
+import (
+	"fmt"
+	"github.com/widmogrod/probability-playground/montecarlo"
+	"github.com/widmogrod/probability-playground/probtest"
+	"gonum.org/v1/plot/vg"
+	"testing"
+)
+
+// When Monte Carlo estimate looks off, first question to ask is:
+// has simulation converged, or more trials are needed?
+//
+// Convergence plot shows how running estimate and its confidence interval
+// change when more trials are performed, and how far it's from theoretical value.
+// Batch means diagnostic compares variation of estimates between batches of trials
+// with variation expected from independent trials, ratio close to 1 means that all is fine.
+func TestMonteCarloConvergence(t *testing.T) {
+	useCases := map[string]struct {
+		experiment  montecarlo.Experiment
+		outcome     montecarlo.Outcome
+		theoretical float64
+	}{
+		"birthday": {
+			experiment:  birthdayExperiment{n: 365, k: 23},
+			outcome:     montecarlo.Success,
+			theoretical: birthdayProblemTheoretical(365, 23),
+		},
+		"coin_toss": {
+			experiment:  coinTossExperiment,
+			outcome:     heads,
+			theoretical: 0.5,
+		},
+		"monty_hall": {
+			experiment:  montyHallExperiment{},
+			outcome:     switchWins,
+			theoretical: 2.0 / 3,
+		},
+		"mturk": {
+			experiment:  mTurkExperiment{k: 3},
+			outcome:     2,
+			theoretical: 36.0 / 64,
+		},
+	}
+	for name, uc := range useCases {
+		t.Run(name, func(t *testing.T) {
+			trace := montecarlo.Tracer{
+				Runner:  montecarlo.Runner{Trials: 20000, Seed: 0},
+				Outcome: uc.outcome,
+				Every:   100,
+			}.Run(uc.experiment)
+
+			final := trace.Final()
+			diagnostic := trace.BatchMeans(20)
+			t.Logf("final estimate %s, batch means ratio %f", final.Estimate, diagnostic.Ratio)
+
+			probtest.AssertProportion(t, final.Estimate.Successes, final.Trials, uc.theoretical, probtest.DefaultFalseAlarmRate)
+			if diagnostic.Ratio > 2 {
+				t.Errorf("batch means standard error is %f times bigger than expected, simulation has not converged", diagnostic.Ratio)
+			}
+
+			p, err := montecarlo.ConvergencePlot(fmt.Sprintf("Convergence of %s simulation", name), trace, uc.theoretical)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := p.Save(18*vg.Inch, 9*vg.Inch, fmt.Sprintf("convergence_%s_test.png", name)); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -1,53 +1,85 @@
 package example
 
 import (
+	"github.com/widmogrod/probability-playground/montecarlo"
+	"github.com/widmogrod/probability-playground/probtest"
 	"math/rand"
 	"testing"
 )
 
-// Probability of wining in Monty Hall game
-func TestMontyHallCarlo_switch_gates(t *testing.T) {
-	n := 100000
-	nonSwitchSuccesses := 0
-	switchSuccesses := 0
+const (
+	// stayWins is an outcome of a game in which player that sticks to decision wins
+	stayWins montecarlo.Outcome = iota
+	// switchWins is an outcome of a game in which player that switch gates wins
+	switchWins
+)
+
+// montyHallExperiment is a single game of Monty Hall.
+// Because one of remaining gates is always reviled as not winning one,
+// exactly one strategy wins the game: sticking to decision or switching gates.
+type montyHallExperiment struct{}
+
+func (montyHallExperiment) Trial(rnd *rand.Rand) montecarlo.Outcome {
 	gates := []int{1, 0, 0}
 	decisions := []int{0, 1, 2}
 
-	for i := 0; i < n; i++ {
-		// New game, different gates
-		rand.Shuffle(len(gates), func(i, j int) {
-			gates[i], gates[j] = gates[j], gates[i]
-		})
-		// New player, new decisions
-		rand.Shuffle(len(decisions), func(i, j int) {
-			decisions[i], decisions[j] = decisions[j], decisions[i]
-		})
-
-		// Player select the gate, and sticks to decision
-		selectedGate := decisions[0]
-		if gates[selectedGate] == 1 {
-			nonSwitchSuccesses++
-		}
-
-		// In case player decides to switch gates,
-		// Let's reduce number of player choices
-		remainingDecisions := decisions[1:]
-
-		// Gate that always will be relived is not winning gate (assumption)
-		// So let's find it
-		reviledGate := remainingDecisions[0]
-		switchToGate := remainingDecisions[1]
-		if gates[reviledGate] == 1 {
-			reviledGate = remainingDecisions[1]
-			switchToGate = remainingDecisions[0]
-		}
-
-		// Let's count player success when switching the doors result in winning the game
-		if gates[switchToGate] == 1 {
-			switchSuccesses++
-		}
+	// New game, different gates
+	rnd.Shuffle(len(gates), func(i, j int) {
+		gates[i], gates[j] = gates[j], gates[i]
+	})
+	// New player, new decisions
+	rnd.Shuffle(len(decisions), func(i, j int) {
+		decisions[i], decisions[j] = decisions[j], decisions[i]
+	})
+
+	// Player select the gate, and sticks to decision
+	selectedGate := decisions[0]
+	if gates[selectedGate] == 1 {
+		return stayWins
+	}
+
+	// In case player decides to switch gates,
+	// Let's reduce number of player choices
+	remainingDecisions := decisions[1:]
+
+	// Gate that always will be relived is not winning gate (assumption)
+	// So let's find it
+	reviledGate := remainingDecisions[0]
+	switchToGate := remainingDecisions[1]
+	if gates[reviledGate] == 1 {
+		reviledGate = remainingDecisions[1]
+		switchToGate = remainingDecisions[0]
+	}
+
+	// Let's count player success when switching the doors result in winning the game
+	if gates[switchToGate] == 1 {
+		return switchWins
+	}
+
+	panic("monty hall: one of strategies must win the game")
+}
+
+// Probability of wining in Monty Hall game
+func TestMontyHallCarlo_switch_gates(t *testing.T) {
+	// Instead of fixed number of games, play until probability
+	// that switching wins is known with precision ±0.003
+	runner := montecarlo.AdaptiveRunner{
+		MaxTrials: 1000000,
+		Seed:      0,
+		Precision: montecarlo.Precision{
+			Outcome:   switchWins,
+			HalfWidth: 0.003,
+		},
+	}
+
+	result := runner.Run(montyHallExperiment{})
+	t.Logf("simulation seed=%d trials=%d", result.Seed, result.Trials)
+	if !result.Reached {
+		t.Errorf("precision not reached within %d trials", runner.MaxTrials)
 	}
+	t.Logf("P(stay wins)=%s P(switch wins)=%s", result.Estimate(stayWins), result.Estimate(switchWins))
 
-	between(t, float64(nonSwitchSuccesses)/float64(n), 0.32, 0.35, 0.001)
-	between(t, float64(switchSuccesses)/float64(n), 0.64, 0.67, 0.001)
+	s := probtest.NewSuite(t, probtest.DefaultFalseAlarmRate, 2)
+	s.AssertProportion(result.Count(stayWins), result.Trials, 1.0/3)
+	s.AssertProportion(result.Count(switchWins), result.Trials, 2.0/3)
 }
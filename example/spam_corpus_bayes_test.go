@@ -0,0 +1,68 @@
+package example
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/widmogrod/probability-playground/bayes"
+	"github.com/widmogrod/probability-playground/bayes/corpus"
+	"github.com/widmogrod/probability-playground/bayes/evaluation"
+	"github.com/widmogrod/probability-playground/bayes/tokenize"
+	"os"
+	"testing"
+)
+
+// openSMSSpam opens corpus of short messages, that was written for this example
+func openSMSSpam(t *testing.T) *os.File {
+	f, err := os.Open("testdata/sms_spam.tsv")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return f
+}
+
+func TestSpamFilteringCorpus(t *testing.T) {
+	f := openSMSSpam(t)
+	defer f.Close()
+
+	classifier := &bayes.Classifier{Alpha: 1, Tokenizer: tokenize.Default()}
+	n, err := corpus.Train(classifier, corpus.NewTSV(f))
+	assert.NoError(t, err)
+	assert.Equal(t, 30, n)
+
+	useCases := map[string]bayes.Class{
+		"You won a prize! Call now to claim":        spamClass(true),
+		"Free tickets, text WIN to 80000":           spamClass(true),
+		"Are we still meeting for lunch tomorrow?":  spamClass(false),
+		"Thanks, see you at the station next week.": spamClass(false),
+	}
+	for text, expected := range useCases {
+		t.Run(text, func(t *testing.T) {
+			best, p := classifier.PredictText(text).Best()
+			t.Logf("%s: P(%s)=%.3f", text, best, p)
+			assert.Equal(t, expected, best)
+		})
+	}
+}
+
+func TestSpamFilteringCorpusEvaluation(t *testing.T) {
+	f := openSMSSpam(t)
+	defer f.Close()
+
+	tokenizer := tokenize.Default()
+	var samples []evaluation.Sample
+	err := corpus.Each(corpus.NewTSV(f), func(m corpus.Message) error {
+		samples = append(samples, evaluation.Sample{Class: m.Class, Doc: tokenizer.Tokenize(m.Text)})
+		return nil
+	})
+	assert.NoError(t, err)
+
+	predictions, err := evaluation.CrossValidate(samples, 5, 0, func() evaluation.Model {
+		return &bayes.Classifier{Alpha: 1}
+	})
+	assert.NoError(t, err)
+
+	m := predictions.ConfusionMatrix()
+	t.Logf("confusion matrix:\n%s", m)
+	t.Logf("accuracy=%.3f AUC=%.3f", m.Accuracy(), predictions.AUC(spamClass(true)))
+	assert.Greater(t, m.Accuracy(), 0.8)
+}
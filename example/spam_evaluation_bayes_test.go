@@ -0,0 +1,72 @@
+package example
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/widmogrod/probability-playground/bayes"
+	"github.com/widmogrod/probability-playground/bayes/evaluation"
+	"gonum.org/v1/plot/vg"
+	"math/rand"
+	"testing"
+)
+
+// spamCorpus generates labeled messages, where spam and ham use the same vocabulary,
+// but spam prefers words from the beginning of it, and ham from the end,
+// so some messages are ambiguous and classifier makes mistakes.
+func spamCorpus(rnd *rand.Rand, messages int, pSpam float64) []evaluation.Sample {
+	const vocabulary = 50
+
+	result := make([]evaluation.Sample, messages)
+	for i := range result {
+		spam := rnd.Float64() < pSpam
+
+		doc := make([]string, 1+rnd.Intn(3))
+		for j := range doc {
+			// triangular distribution over vocabulary, leaning towards one end
+			w := int(float64(vocabulary) * (1 - rnd.Float64()*rnd.Float64()))
+			if spam {
+				w = vocabulary - 1 - w
+			}
+			doc[j] = fmt.Sprintf("w%d", w)
+		}
+
+		result[i] = evaluation.Sample{Class: spamClass(spam), Doc: doc}
+	}
+
+	return result
+}
+
+func TestSpamFilteringEvaluation(t *testing.T) {
+	seed := int64(0)
+	samples := spamCorpus(rand.New(rand.NewSource(seed)), 2000, 0.2)
+
+	predictions, err := evaluation.CrossValidate(samples, 5, seed, func() evaluation.Model {
+		return &bayes.Classifier{Alpha: 1}
+	})
+	assert.NoError(t, err)
+
+	spam := spamClass(true)
+	m := predictions.ConfusionMatrix()
+	t.Logf("confusion matrix:\n%s", m)
+	t.Logf("accuracy=%.3f precision=%.3f recall=%.3f F1=%.3f log-loss=%.3f AUC=%.3f",
+		m.Accuracy(), m.Precision(spam), m.Recall(spam), m.F1(spam), predictions.LogLoss(), predictions.AUC(spam))
+
+	assert.Greater(t, predictions.AUC(spam), 0.9)
+	assert.Greater(t, m.F1(spam), 0.7)
+
+	roc, err := evaluation.ROCPlot(fmt.Sprintf("Spam filter, 5-fold cross validation (seed=%d)", seed), predictions.ROC(spam))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := roc.Save(6*vg.Inch, 6*vg.Inch, "spam_evaluation_bayes_test_roc.png"); err != nil {
+		t.Fatal(err)
+	}
+
+	pr, err := evaluation.PrecisionRecallPlot(fmt.Sprintf("Spam filter, 5-fold cross validation (seed=%d)", seed), predictions.PrecisionRecall(spam))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pr.Save(6*vg.Inch, 6*vg.Inch, "spam_evaluation_bayes_test_pr.png"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,7 +1,9 @@
 package example
 
 import (
-	"strings"
+	"github.com/stretchr/testify/assert"
+	"github.com/widmogrod/probability-playground/bayes"
+	"github.com/widmogrod/probability-playground/bayes/tokenize"
 	"testing"
 )
 
@@ -19,6 +21,10 @@ func TestSpamFilteringBayesTest(t *testing.T) {
 	useCases := map[string]struct {
 		train []train
 		test  test
+		// alpha is additive smoothing, zero means no smoothing
+		alpha float64
+		// tokenizer splits text into words, nil means splitting on white spaces
+		tokenizer tokenize.Tokenizer
 	}{
 		"toy": {
 			train: []train{
@@ -29,129 +35,125 @@ func TestSpamFilteringBayesTest(t *testing.T) {
 			},
 			test: test{
 				text:  "what is your password",
-				pSpam: 0,
+				pSpam: 1,
 				pHam:  0,
 			},
 		},
+		"toy - unknown words": {
+			train: []train{
+				{true, "send me your password"},
+				{false, "send me your picture"},
+				{true, "what is your password"},
+				{false, "what is your name"},
+			},
+			test: test{
+				text:  "how are you",
+				pSpam: 0.5,
+				pHam:  0.5,
+			},
+		},
+		"toy - priors learned from training": {
+			train: []train{
+				{true, "send me your password"},
+				{true, "what is your password"},
+				{true, "send me money"},
+				{false, "what is your name"},
+			},
+			test: test{
+				text: "what is your",
+				// spam is more probable a priori (3/4),
+				// but words are more frequent in shorter ham message
+				pSpam: 0.2239,
+				pHam:  0.7761,
+			},
+		},
+		"toy - words unseen in a class make every class impossible, so priors are returned": {
+			train: []train{
+				{true, "send me your password"},
+				{false, "send me your picture"},
+				{true, "what is your password"},
+				{false, "what is your name"},
+			},
+			test: test{
+				// "password" was never seen in ham, and "name" in spam
+				text:  "send me your password and name",
+				pSpam: 0.5,
+				pHam:  0.5,
+			},
+		},
+		"toy - laplace smoothing": {
+			train: []train{
+				{true, "send me your password"},
+				{false, "send me your picture"},
+				{true, "what is your password"},
+				{false, "what is your name"},
+			},
+			test: test{
+				text:  "send me your password and name",
+				pSpam: 0.6,
+				pHam:  0.4,
+			},
+			alpha: 1,
+		},
+		"real messages - split on white spaces": {
+			train: []train{
+				{true, "Send me your PASSWORD!!!"},
+				{false, "Send me your picture, please."},
+				{true, "What is your password? Reply to admin@bank.example"},
+				{false, "What is your name?"},
+			},
+			test: test{
+				// "password", "PASSWORD!!!" and "password?" are different words
+				text:  "Your password, now!",
+				pSpam: 0.5,
+				pHam:  0.5,
+			},
+			alpha: 1,
+		},
+		"real messages - default tokenizer": {
+			train: []train{
+				{true, "Send me your PASSWORD!!!"},
+				{false, "Send me your picture, please."},
+				{true, "What is your password? Reply to admin@bank.example"},
+				{false, "What is your name?"},
+			},
+			test: test{
+				// "password" is seen twice in spam
+				text:  "Your password, now!",
+				pSpam: 0.7292,
+				pHam:  0.2708,
+			},
+			alpha:     1,
+			tokenizer: tokenize.Default(),
+		},
 	}
 	for name, uc := range useCases {
 		t.Run(name, func(t *testing.T) {
-			// prepare
-			bowc := BowClass{}
+			// train
+			classifier := &bayes.Classifier{Alpha: uc.alpha, Tokenizer: uc.tokenizer}
 			for _, sample := range uc.train {
-				words := strings.Split(sample.text, " ")
-				for _, w := range words {
-					bowc.Inc(sample.spam, w)
-				}
+				classifier.TrainText(spamClass(sample.spam), sample.text)
 			}
 
-			// train
+			// test
 			//                   P(H) * P(E|H)
 			// P(H|E) = --------------------------------
 			//           P(H) * P(E|H) + P(-H) * P(E|-H)
+			explanation := classifier.ExplainText(uc.test.text)
+			t.Logf("%q\n%s", uc.test.text, explanation)
 
-			PrSpamInit := 0.5
-
-			words := strings.Split(uc.test.text, " ")
-			for _, w := range words {
-				if !bowc.Has(w) {
-					continue
-				}
-
-				PrSpamInit = (PrSpamInit * bowc.Proportion(true, w)) / (PrSpamInit*bowc.Proportion(true, w) + ((1 - PrSpamInit) * bowc.Proportion(false, w)))
-
-				t.Logf("                  %f * %f", PrSpamInit, bowc.Proportion(true, w))
-				t.Logf("P(H|E) = --------------------------------")
-				t.Logf("          %f * %f + %f * %f", PrSpamInit, bowc.Proportion(true, w), (1 - PrSpamInit), bowc.Proportion(false, w))
-				t.Logf("")
-				t.Logf("word: %s   P(spam)=%f\n\n", w, PrSpamInit)
-			}
-
-			PrHamInit := 0.5
-
-			words = strings.Split(uc.test.text, " ")
-			for _, w := range words {
-				if !bowc.Has(w) {
-					continue
-				}
+			posterior := explanation.Posterior
 
-				PrHamInit = (PrHamInit * bowc.Proportion(false, w)) / (PrHamInit*bowc.Proportion(false, w) + ((1 - PrHamInit) * bowc.Proportion(true, w)))
-
-				t.Logf("word: %s   P(ham)=%f", w, PrHamInit)
-			}
-
-			// test
+			assert.InDelta(t, uc.test.pSpam, posterior[spamClass(true)], 0.0001)
+			assert.InDelta(t, uc.test.pHam, posterior[spamClass(false)], 0.0001)
 		})
 	}
 }
 
-type BoW map[string]float64
-
-func (b BoW) Inc(w string) {
-	if _, ok := b[w]; ok {
-		b[w]++
-	} else {
-		b[w] = 1
+func spamClass(spam bool) bayes.Class {
+	if spam {
+		return "spam"
 	}
-}
-
-func (b BoW) Val(w string) float64 {
-	if _, ok := b[w]; ok {
-		return b[w]
-	}
-
-	return 0
-}
-
-func (b BoW) Total() float64 {
-	result := .0
-
-	// TODO: optimise!
-	for _, count := range b {
-		result += count
-	}
-
-	return result
-}
-
-type BowClass map[bool]BoW
-
-func (bc BowClass) Inc(class bool, w string) {
-	if b, ok := bc[class]; ok {
-		b.Inc(w)
-	} else {
-		bc[class] = BoW{}
-		bc[class].Inc(w)
-	}
-}
-
-func (bc BowClass) Val(class bool, w string) float64 {
-	if b, ok := bc[class]; ok {
-		return b.Val(w)
-	}
-
-	return 0
-}
-
-func (bc BowClass) Has(w string) bool {
-	if bc.Val(true, w) != .0 {
-		return true
-	}
-	if bc.Val(false, w) != .0 {
-		return true
-	}
-
-	return false
-}
-
-func (bc BowClass) Total(class bool) float64 {
-	if b, ok := bc[class]; ok {
-		return b.Total()
-	}
-
-	return 0
-}
 
-func (bc BowClass) Proportion(class bool, w string) float64 {
-	return bc.Val(class, w) / bc.Total(class)
+	return "ham"
 }
@@ -0,0 +1,47 @@
+package example
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/widmogrod/probability-playground/bayes"
+	"github.com/widmogrod/probability-playground/bayes/evaluation"
+	"math/rand"
+	"testing"
+)
+
+func TestSpamFilteringVariants(t *testing.T) {
+	seed := int64(0)
+
+	useCases := map[string]struct {
+		// pSpam is proportion of spam in corpus
+		pSpam float64
+	}{
+		"balanced":   {pSpam: 0.5},
+		"imbalanced": {pSpam: 0.05},
+	}
+	for name, uc := range useCases {
+		t.Run(name, func(t *testing.T) {
+			samples := spamCorpus(rand.New(rand.NewSource(seed)), 2000, uc.pSpam)
+
+			recall := make(map[bayes.Variant]float64)
+			for _, variant := range []bayes.Variant{bayes.Multinomial, bayes.Bernoulli, bayes.Complement} {
+				predictions, err := evaluation.CrossValidate(samples, 5, seed, func() evaluation.Model {
+					return &bayes.Classifier{Alpha: 1, Variant: variant}
+				})
+				assert.NoError(t, err)
+
+				spam := spamClass(true)
+				m := predictions.ConfusionMatrix()
+				t.Logf("%-11s precision=%.3f recall=%.3f F1=%.3f log-loss=%.3f AUC=%.3f",
+					variant, m.Precision(spam), m.Recall(spam), m.F1(spam), predictions.LogLoss(), predictions.AUC(spam))
+
+				assert.Greater(t, predictions.AUC(spam), 0.9, "variant %s", variant)
+				recall[variant] = m.Recall(spam)
+			}
+
+			// complement variant ignores priors, so rare spam is not outvoted by prior of ham
+			if uc.pSpam < 0.5 {
+				assert.Greater(t, recall[bayes.Complement], recall[bayes.Multinomial])
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ module github.com/widmogrod/probability-playground
 go 1.13
 
 require (
+	github.com/stretchr/testify v1.8.4
 	golang.org/x/exp/errors v0.0.0-20200228211341-fcea875c7e85
 	gonum.org/v1/gonum v0.7.0
 	gonum.org/v1/netlib v0.0.0-20200229103305-d71f404090bf // indirect
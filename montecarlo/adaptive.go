@@ -0,0 +1,95 @@
+package montecarlo
+
+// Precision describes how narrow confidence interval of an estimate must be.
+// When both HalfWidth and RelativeError are set, reaching any of them is enough.
+type Precision struct {
+	// Outcome which probability is estimated
+	Outcome Outcome
+	// HalfWidth is target half-width of confidence interval, zero means not used
+	HalfWidth float64
+	// RelativeError is target half-width of confidence interval
+	// relative to the estimated value, zero means not used
+	RelativeError float64
+	// Confidence level of the interval, when zero 0.95 is used
+	Confidence float64
+	// Method used to compute confidence interval
+	Method IntervalMethod
+}
+
+// Reached returns true when interval of estimate is narrow enough.
+func (p Precision) Reached(e Estimate) bool {
+	confidence := p.Confidence
+	if confidence == 0 {
+		confidence = 0.95
+	}
+
+	width := e.Interval(p.Method, confidence).HalfWidth()
+	if p.HalfWidth > 0 && width <= p.HalfWidth {
+		return true
+	}
+	if p.RelativeError > 0 && e.Value > 0 && width <= p.RelativeError*e.Value {
+		return true
+	}
+
+	return false
+}
+
+// AdaptiveRunner draws batches of trials until estimate is precise enough,
+// instead of running hard-coded number of trials.
+//
+// Batch after batch are run with consecutive random streams,
+// so with the same seed and batch size, result is reproducible
+// and does not depend on number of workers.
+type AdaptiveRunner struct {
+	// MaxTrials is a budget, run stops when it's exhausted even if precision was not reached
+	MaxTrials int
+	// BatchSize is number of trials drawn before precision is checked again,
+	// when zero trialsPerStream is used
+	BatchSize int
+	// Seed initialises random source passed to experiment
+	Seed int64
+	// Workers is number of goroutines that run trials
+	Workers int
+	// Precision that must be reached to stop drawing trials
+	Precision Precision
+}
+
+// AdaptiveResult is result of adaptive run, Trials tells how many trials were actually needed.
+type AdaptiveResult struct {
+	Result
+	// Reached is true when precision was reached within the budget
+	Reached bool
+}
+
+// Run draws batches of trials until precision is reached or budget exhausted.
+func (r AdaptiveRunner) Run(e Experiment) AdaptiveResult {
+	batchSize := r.BatchSize
+	if batchSize <= 0 {
+		batchSize = trialsPerStream
+	}
+
+	runner := Runner{Seed: r.Seed, Workers: r.Workers}
+	result := AdaptiveResult{
+		Result: NewResult(),
+	}
+	result.Seed = r.Seed
+
+	stream := 0
+	for result.Trials < r.MaxTrials {
+		trials := batchSize
+		if remaining := r.MaxTrials - result.Trials; trials > remaining {
+			trials = remaining
+		}
+
+		var batch Result
+		batch, stream = runner.run(e, stream, trials)
+		result.Merge(batch)
+
+		if r.Precision.Reached(result.Estimate(r.Precision.Outcome)) {
+			result.Reached = true
+			break
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,64 @@
+package montecarlo
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+var coin = ExperimentFunc(func(rnd *rand.Rand) Outcome {
+	return Binary(rnd.Float64() < 0.5)
+})
+
+func TestAdaptiveRunner_Run(t *testing.T) {
+	runner := AdaptiveRunner{
+		MaxTrials: 1000000,
+		BatchSize: 1000,
+		Seed:      3,
+		Precision: Precision{
+			Outcome:   Success,
+			HalfWidth: 0.01,
+		},
+	}
+
+	result := runner.Run(coin)
+
+	// Half-width of 95% interval for p=0.5 is 1.96*√(0.25/n),
+	// so at least 9604 trials are needed, rounded up to whole batches
+	assert.True(t, result.Reached)
+	assert.Equal(t, 10000, result.Trials)
+	assert.True(t, result.Estimate(Success).Wilson(0.95).HalfWidth() <= 0.01)
+
+	runner.Workers = 3
+	assert.Equal(t, result, runner.Run(coin))
+}
+
+func TestAdaptiveRunner_Run_relativeError(t *testing.T) {
+	result := AdaptiveRunner{
+		MaxTrials: 1000000,
+		Seed:      3,
+		Precision: Precision{
+			Outcome:       Success,
+			RelativeError: 0.05,
+		},
+	}.Run(coin)
+
+	e := result.Estimate(Success)
+	assert.True(t, result.Reached)
+	assert.True(t, e.Wilson(0.95).HalfWidth() <= 0.05*e.Value)
+	assert.Equal(t, 0, result.Trials%trialsPerStream)
+}
+
+func TestAdaptiveRunner_Run_budget(t *testing.T) {
+	result := AdaptiveRunner{
+		MaxTrials: 2500,
+		BatchSize: 1000,
+		Precision: Precision{
+			Outcome:   Success,
+			HalfWidth: 0.001,
+		},
+	}.Run(coin)
+
+	assert.False(t, result.Reached)
+	assert.Equal(t, 2500, result.Trials)
+}
@@ -0,0 +1,161 @@
+package montecarlo
+
+import (
+	"fmt"
+	"gonum.org/v1/gonum/stat/distuv"
+	"math"
+)
+
+// IntervalMethod selects how confidence interval of a proportion is computed.
+type IntervalMethod int
+
+const (
+	// Wilson score interval, good coverage even for small number of trials
+	// and proportions close to 0 or 1, that's why it's the default choice.
+	Wilson IntervalMethod = iota
+	// ClopperPearson exact interval, based on quantiles of beta distribution.
+	// It's conservative, coverage is never lower than requested confidence.
+	ClopperPearson
+	// NormalApproximation (Wald) interval p ± z*SE,
+	// works well only when n*p and n*(1-p) are large.
+	NormalApproximation
+)
+
+func (m IntervalMethod) String() string {
+	switch m {
+	case Wilson:
+		return "Wilson"
+	case ClopperPearson:
+		return "Clopper-Pearson"
+	case NormalApproximation:
+		return "normal approximation"
+	}
+
+	return fmt.Sprintf("IntervalMethod(%d)", int(m))
+}
+
+// Interval is a confidence interval of an estimate.
+type Interval struct {
+	Lower float64
+	Upper float64
+	// Confidence level of the interval, for example 0.95
+	Confidence float64
+}
+
+// Contains returns true when value lays inside of the interval.
+func (i Interval) Contains(v float64) bool {
+	return i.Lower <= v && v <= i.Upper
+}
+
+// HalfWidth is half of distance between bounds of the interval.
+func (i Interval) HalfWidth() float64 {
+	return (i.Upper - i.Lower) / 2
+}
+
+// Estimate of probability of an outcome, computed from number of successes in trials.
+type Estimate struct {
+	Successes int
+	Trials    int
+	// Value is point estimate of probability, proportion of successes
+	Value float64
+	// StdErr is standard error of the Value, √(p(1-p)/n)
+	StdErr float64
+}
+
+// NewEstimate creates estimate of probability of success, from observed successes in trials.
+func NewEstimate(successes, trials int) Estimate {
+	e := Estimate{
+		Successes: successes,
+		Trials:    trials,
+	}
+	if trials > 0 {
+		e.Value = float64(successes) / float64(trials)
+		e.StdErr = math.Sqrt(e.Value * (1 - e.Value) / float64(trials))
+	}
+
+	return e
+}
+
+// Interval computes confidence interval of the estimate using given method.
+// When there were no trials, interval covers all probabilities [0, 1].
+func (e Estimate) Interval(method IntervalMethod, confidence float64) Interval {
+	if e.Trials == 0 {
+		return Interval{Lower: 0, Upper: 1, Confidence: confidence}
+	}
+
+	switch method {
+	case ClopperPearson:
+		return e.ClopperPearson(confidence)
+	case NormalApproximation:
+		return e.Normal(confidence)
+	}
+
+	return e.Wilson(confidence)
+}
+
+// Wilson computes Wilson score interval
+//
+//	center = (p + z²/2n) / (1 + z²/n)
+//	width  = z / (1 + z²/n) * √(p(1-p)/n + z²/4n²)
+func (e Estimate) Wilson(confidence float64) Interval {
+	z := zScore(confidence)
+	n := float64(e.Trials)
+	p := e.Value
+
+	denominator := 1 + z*z/n
+	center := (p + z*z/(2*n)) / denominator
+	width := z / denominator * math.Sqrt(p*(1-p)/n+z*z/(4*n*n))
+
+	return Interval{
+		Lower:      math.Max(0, center-width),
+		Upper:      math.Min(1, center+width),
+		Confidence: confidence,
+	}
+}
+
+// ClopperPearson computes exact interval, using relationship between binomial and beta distributions
+//
+//	lower = Beta(x, n-x+1).Quantile(α/2)
+//	upper = Beta(x+1, n-x).Quantile(1-α/2)
+func (e Estimate) ClopperPearson(confidence float64) Interval {
+	alpha := 1 - confidence
+	x := float64(e.Successes)
+	n := float64(e.Trials)
+
+	result := Interval{Lower: 0, Upper: 1, Confidence: confidence}
+	if e.Successes > 0 {
+		result.Lower = distuv.Beta{Alpha: x, Beta: n - x + 1}.Quantile(alpha / 2)
+	}
+	if e.Successes < e.Trials {
+		result.Upper = distuv.Beta{Alpha: x + 1, Beta: n - x}.Quantile(1 - alpha/2)
+	}
+
+	return result
+}
+
+// Normal computes normal approximation interval p ± z*SE, clipped to [0, 1].
+func (e Estimate) Normal(confidence float64) Interval {
+	width := zScore(confidence) * e.StdErr
+
+	return Interval{
+		Lower:      math.Max(0, e.Value-width),
+		Upper:      math.Min(1, e.Value+width),
+		Confidence: confidence,
+	}
+}
+
+// Format estimate in form that is easy to put in a report, like "0.507 ± 0.015 (95%)".
+// Margin of error is computed with normal approximation.
+func (e Estimate) Format(confidence float64) string {
+	return fmt.Sprintf("%.3f ± %.3f (%g%%)", e.Value, zScore(confidence)*e.StdErr, confidence*100)
+}
+
+func (e Estimate) String() string {
+	return e.Format(0.95)
+}
+
+// zScore returns quantile of standard normal distribution,
+// such that two-sided interval ±z covers requested confidence.
+func zScore(confidence float64) float64 {
+	return distuv.UnitNormal.Quantile(1 - (1-confidence)/2)
+}
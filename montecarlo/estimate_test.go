@@ -0,0 +1,67 @@
+package montecarlo
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEstimate_Interval(t *testing.T) {
+	useCases := map[string]struct {
+		successes, trials int
+		method            IntervalMethod
+		lower, upper      float64
+	}{
+		"wilson 5/10": {
+			successes: 5, trials: 10,
+			method: Wilson,
+			lower:  0.2366, upper: 0.7634,
+		},
+		"clopper-pearson 5/10": {
+			successes: 5, trials: 10,
+			method: ClopperPearson,
+			lower:  0.1871, upper: 0.8129,
+		},
+		"normal 5/10": {
+			successes: 5, trials: 10,
+			method: NormalApproximation,
+			lower:  0.1901, upper: 0.8099,
+		},
+		"wilson 0/20": {
+			successes: 0, trials: 20,
+			method: Wilson,
+			lower:  0, upper: 0.1611,
+		},
+		"clopper-pearson 0/20": {
+			successes: 0, trials: 20,
+			method: ClopperPearson,
+			lower:  0, upper: 0.1684,
+		},
+		"clopper-pearson 20/20": {
+			successes: 20, trials: 20,
+			method: ClopperPearson,
+			lower:  0.8316, upper: 1,
+		},
+		"no trials": {
+			successes: 0, trials: 0,
+			method: NormalApproximation,
+			lower:  0, upper: 1,
+		},
+	}
+	for name, uc := range useCases {
+		t.Run(name, func(t *testing.T) {
+			interval := NewEstimate(uc.successes, uc.trials).Interval(uc.method, 0.95)
+			assert.InDelta(t, uc.lower, interval.Lower, 0.0001)
+			assert.InDelta(t, uc.upper, interval.Upper, 0.0001)
+			assert.Equal(t, 0.95, interval.Confidence)
+		})
+	}
+}
+
+func TestEstimate_Format(t *testing.T) {
+	e := NewEstimate(507, 1000)
+
+	assert.Equal(t, 0.507, e.Value)
+	assert.InDelta(t, 0.0158, e.StdErr, 0.0001)
+	assert.Equal(t, "0.507 ± 0.031 (95%)", e.Format(0.95))
+	assert.Equal(t, "0.507 ± 0.041 (99%)", e.Format(0.99))
+}
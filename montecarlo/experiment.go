@@ -0,0 +1,45 @@
+package montecarlo
+
+import "math/rand"
+
+// Outcome is a result of a single trial of an experiment.
+// Meaning of the value is defined by the experiment, for example:
+// - number of the degree of consensus reached in a task,
+// - whether at least two people share the same birthday (Success or Failure),
+// - which side of a coin landed up.
+type Outcome = int
+
+const (
+	// Failure is the outcome of binary experiment that did not happen
+	Failure Outcome = 0
+	// Success is the outcome of binary experiment that happened
+	Success Outcome = 1
+)
+
+// Experiment describes a single random trial of a simulation.
+// Each call to Trial should be independent from previous calls
+// and return outcome that will be aggregated by Runner.
+//
+// All randomness must come from given rnd, never from global math/rand functions,
+// that's how the same seed reproduces the same result.
+// Runner may call Trial from many goroutines, each with its own rnd,
+// so experiment should not mutate shared state.
+type Experiment interface {
+	Trial(rnd *rand.Rand) Outcome
+}
+
+// ExperimentFunc is an adapter that allows use of ordinary function as an Experiment.
+type ExperimentFunc func(rnd *rand.Rand) Outcome
+
+func (f ExperimentFunc) Trial(rnd *rand.Rand) Outcome {
+	return f(rnd)
+}
+
+// Binary converts boolean result of a trial into Success or Failure outcome.
+func Binary(happened bool) Outcome {
+	if happened {
+		return Success
+	}
+
+	return Failure
+}
@@ -0,0 +1,50 @@
+package montecarlo
+
+import (
+	"fmt"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/plotutil"
+	"math"
+)
+
+// ConvergencePlot renders running estimate from trace, together with its confidence interval.
+// When theoretical value is known it's drawn as reference line, otherwise pass math.NaN().
+func ConvergencePlot(title string, trace Trace, theoretical float64) (*plot.Plot, error) {
+	p, err := plot.New()
+	if err != nil {
+		return nil, err
+	}
+
+	p.Title.Text = fmt.Sprintf("%s (seed=%d)", title, trace.Seed)
+	p.X.Label.Text = "number of trials"
+	p.Y.Label.Text = "running estimate"
+	p.Legend.Top = true
+
+	var estimate, lower, upper, reference plotter.XYs
+	for _, cp := range trace.Checkpoints {
+		x := float64(cp.Trials)
+		estimate = append(estimate, plotter.XY{X: x, Y: cp.Estimate.Value})
+		lower = append(lower, plotter.XY{X: x, Y: cp.Interval.Lower})
+		upper = append(upper, plotter.XY{X: x, Y: cp.Interval.Upper})
+		if !math.IsNaN(theoretical) {
+			reference = append(reference, plotter.XY{X: x, Y: theoretical})
+		}
+	}
+
+	confidence := trace.Final().Interval.Confidence * 100
+	lines := []interface{}{
+		"Estimate", estimate,
+		fmt.Sprintf("Lower bound (%g%%)", confidence), lower,
+		fmt.Sprintf("Upper bound (%g%%)", confidence), upper,
+	}
+	if reference != nil {
+		lines = append(lines, fmt.Sprintf("Theoretical (%f)", theoretical), reference)
+	}
+
+	if err := plotutil.AddLines(p, lines...); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
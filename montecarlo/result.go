@@ -0,0 +1,110 @@
+package montecarlo
+
+// Result holds aggregated outcomes of many trials of an experiment.
+type Result struct {
+	// Seed used to initialise random source, allows to reproduce the result
+	Seed int64
+	// Trials is number of all trials that were performed
+	Trials int
+	// Counts holds how many times each outcome occurred
+	Counts map[Outcome]int
+}
+
+func NewResult() Result {
+	return Result{
+		Counts: make(map[Outcome]int),
+	}
+}
+
+// Add records single outcome of a trial.
+func (r *Result) Add(o Outcome) {
+	if r.Counts == nil {
+		r.Counts = make(map[Outcome]int)
+	}
+
+	r.Counts[o]++
+	r.Trials++
+}
+
+// Merge adds outcomes counted in other result.
+func (r *Result) Merge(other Result) {
+	if r.Counts == nil {
+		r.Counts = make(map[Outcome]int)
+	}
+
+	for o, count := range other.Counts {
+		r.Counts[o] += count
+	}
+	r.Trials += other.Trials
+}
+
+// Count returns how many times outcome occurred.
+func (r Result) Count(o Outcome) int {
+	return r.Counts[o]
+}
+
+// Probability returns empirical probability of an outcome,
+// which is proportion of trials that ended with given outcome.
+func (r Result) Probability(o Outcome) float64 {
+	if r.Trials == 0 {
+		return 0
+	}
+
+	return float64(r.Counts[o]) / float64(r.Trials)
+}
+
+// Estimate returns probability of an outcome together with its uncertainty.
+func (r Result) Estimate(o Outcome) Estimate {
+	return NewEstimate(r.Counts[o], r.Trials)
+}
+
+// Estimates returns estimates of probabilities of outcomes 0..n-1.
+func (r Result) Estimates(n int) []Estimate {
+	result := make([]Estimate, n)
+	for o := range result {
+		result[o] = r.Estimate(o)
+	}
+
+	return result
+}
+
+// Mean returns average outcome, which is meaningful
+// when outcome is a number, like count of something that happened in a trial.
+func (r Result) Mean() float64 {
+	if r.Trials == 0 {
+		return 0
+	}
+
+	sum := .0
+	for o, count := range r.Counts {
+		sum += float64(o) * float64(count)
+	}
+
+	return sum / float64(r.Trials)
+}
+
+// Variance returns sample variance of outcomes.
+func (r Result) Variance() float64 {
+	if r.Trials < 2 {
+		return 0
+	}
+
+	mean := r.Mean()
+	sum := .0
+	for o, count := range r.Counts {
+		sum += (float64(o) - mean) * (float64(o) - mean) * float64(count)
+	}
+
+	return sum / float64(r.Trials-1)
+}
+
+// Probabilities returns empirical probabilities of outcomes 0..n-1,
+// which is convenient for experiments which outcomes are small integers.
+func (r Result) Probabilities(n int) []float64 {
+	result := make([]float64, n)
+	for o := range result {
+		result[o] = r.Probability(o)
+	}
+
+	return result
+}
@@ -0,0 +1,112 @@
+package montecarlo
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sync"
+)
+
+// trialsPerStream is number of consecutive trials that share one random stream.
+// Trials are divided into such blocks up-front, independently of number of workers,
+// that's why the same seed gives the same result no matter how many workers run it.
+const trialsPerStream = 4096
+
+// Runner executes experiment many times and aggregates outcomes.
+type Runner struct {
+	// Trials is number of times experiment is repeated, Run panics when it's negative
+	Trials int
+	// Seed initialises random source passed to experiment.
+	// Runs with the same seed produce exactly the same result.
+	Seed int64
+	// Workers is number of goroutines that run trials,
+	// when zero GOMAXPROCS goroutines are used.
+	// With more than one worker experiment must be safe for concurrent use.
+	Workers int
+}
+
+// Run repeats experiment and counts how many times each outcome occurred.
+//
+// Trials are sharded into blocks, each block has own random stream
+// derived from master seed, and blocks are distributed between workers.
+// Counts from all blocks are merged, and because addition is commutative
+// order in which workers finish does not change the result.
+func (r Runner) Run(e Experiment) Result {
+	result, _ := r.run(e, 0, r.Trials)
+	return result
+}
+
+// run performs trials using consecutive streams, starting from firstStream,
+// and returns index of the next unused stream, so that following batch of trials
+// can continue with fresh streams.
+func (r Runner) run(e Experiment, firstStream, trials int) (Result, int) {
+	if trials < 0 {
+		panic(fmt.Sprintf("montecarlo: number of trials %d is negative", trials))
+	}
+
+	workers := r.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	streams := (trials + trialsPerStream - 1) / trialsPerStream
+	if workers > streams {
+		workers = streams
+	}
+
+	jobs := make(chan int, streams)
+	for stream := 0; stream < streams; stream++ {
+		jobs <- stream
+	}
+	close(jobs)
+
+	results := make([]Result, workers)
+	wg := sync.WaitGroup{}
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func(w int) {
+			defer wg.Done()
+			results[w] = NewResult()
+			for stream := range jobs {
+				n := trials - stream*trialsPerStream
+				if n > trialsPerStream {
+					n = trialsPerStream
+				}
+				r.runStream(e, firstStream+stream, n, &results[w])
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	result := NewResult()
+	result.Seed = r.Seed
+	for _, partial := range results {
+		result.Merge(partial)
+	}
+
+	return result, firstStream + streams
+}
+
+func (r Runner) runStream(e Experiment, stream, trials int, result *Result) {
+	rnd := rand.New(rand.NewSource(StreamSeed(r.Seed, stream)))
+	for i := 0; i < trials; i++ {
+		result.Add(e.Trial(rnd))
+	}
+}
+
+// StreamSeed derives seed of independent random stream from master seed.
+// Derivation uses SplitMix64 finaliser, so even neighbouring streams
+// start from seeds that are far from each other.
+func StreamSeed(seed int64, stream int) int64 {
+	z := uint64(seed) + uint64(stream+1)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	z = z ^ (z >> 31)
+
+	return int64(z)
+}
+
+// Run is a shortcut for running experiment with Runner configured to given number of trials and seed.
+func Run(trials int, seed int64, e Experiment) Result {
+	return Runner{Trials: trials, Seed: seed}.Run(e)
+}
@@ -0,0 +1,94 @@
+package montecarlo
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	i := 0
+	cycle := ExperimentFunc(func(_ *rand.Rand) Outcome {
+		i++
+		return i % 4
+	})
+
+	result := Runner{Trials: 1000, Workers: 1}.Run(cycle)
+
+	assert.Equal(t, 1000, result.Trials)
+	assert.Equal(t, 250, result.Count(0))
+	assert.Equal(t, 250, result.Count(3))
+	assert.Equal(t, 0, result.Count(4))
+	assert.Equal(t, []float64{0.25, 0.25, 0.25, 0.25, 0}, result.Probabilities(5))
+	assert.Equal(t, 1.5, result.Mean())
+	assert.InDelta(t, 1.2512, result.Variance(), 0.0001)
+}
+
+func TestRunner_Run_trials(t *testing.T) {
+	dice := ExperimentFunc(func(rnd *rand.Rand) Outcome {
+		return rnd.Intn(6)
+	})
+
+	result := Runner{Trials: 0}.Run(dice)
+	assert.Equal(t, 0, result.Trials)
+
+	// negative number of trials of any size is rejected, not rounded to zero streams
+	for _, trials := range []int{-1, -5000, -100000} {
+		assert.Panics(t, func() {
+			Runner{Trials: trials}.Run(dice)
+		}, "trials=%d", trials)
+	}
+}
+
+func TestRun_reproducible(t *testing.T) {
+	dice := ExperimentFunc(func(rnd *rand.Rand) Outcome {
+		return rnd.Intn(6)
+	})
+
+	a := Run(1000, 42, dice)
+	b := Run(1000, 42, dice)
+	c := Run(1000, 43, dice)
+
+	assert.Equal(t, int64(42), a.Seed)
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a.Counts, c.Counts)
+}
+
+func TestRunner_Run_workersDoNotChangeResult(t *testing.T) {
+	dice := ExperimentFunc(func(rnd *rand.Rand) Outcome {
+		return rnd.Intn(6)
+	})
+
+	expected := Runner{Trials: 100000, Seed: 7, Workers: 1}.Run(dice)
+	assert.Equal(t, 100000, expected.Trials)
+
+	for _, workers := range []int{0, 2, 3, 8, 64} {
+		result := Runner{Trials: 100000, Seed: 7, Workers: workers}.Run(dice)
+		assert.Equal(t, expected, result, "workers=%d", workers)
+	}
+}
+
+func TestStreamSeed(t *testing.T) {
+	seen := map[int64]bool{}
+	for seed := int64(0); seed < 10; seed++ {
+		for stream := 0; stream < 100; stream++ {
+			s := StreamSeed(seed, stream)
+			assert.False(t, seen[s], "seed=%d stream=%d", seed, stream)
+			seen[s] = true
+		}
+	}
+}
+
+func TestResult_Probability(t *testing.T) {
+	result := Result{}
+	assert.Equal(t, .0, result.Probability(Success))
+
+	result.Add(Success)
+	result.Add(Failure)
+	result.Add(Binary(true))
+	result.Add(Binary(false))
+
+	assert.Equal(t, 4, result.Trials)
+	assert.Equal(t, 0.5, result.Probability(Success))
+	assert.Equal(t, 0.5, result.Probability(Failure))
+}
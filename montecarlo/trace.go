@@ -0,0 +1,149 @@
+package montecarlo
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Checkpoint is a running estimate recorded after given number of trials.
+type Checkpoint struct {
+	Trials   int
+	Estimate Estimate
+	Interval Interval
+}
+
+// Trace records how estimate of an outcome changes when more trials are performed.
+// It answers the question: has simulation converged, or more trials are needed?
+type Trace struct {
+	Seed        int64
+	Outcome     Outcome
+	Checkpoints []Checkpoint
+}
+
+// Final returns the last recorded checkpoint, which holds estimate from all trials.
+func (t Trace) Final() Checkpoint {
+	if len(t.Checkpoints) == 0 {
+		return Checkpoint{}
+	}
+
+	return t.Checkpoints[len(t.Checkpoints)-1]
+}
+
+// Tracer runs experiment and records running estimate of an outcome every k trials.
+//
+// Trials are performed in the same order and with the same random streams as Runner does,
+// so final estimate in the trace is equal to the estimate that Runner with the same seed returns.
+// To keep the order, trials are run by single goroutine, Runner.Workers is ignored.
+type Tracer struct {
+	Runner
+	// Outcome which running estimate is recorded
+	Outcome Outcome
+	// Every is number of trials between checkpoints
+	Every int
+	// Confidence level of recorded intervals, when zero 0.95 is used
+	Confidence float64
+	// Method used to compute recorded intervals
+	Method IntervalMethod
+}
+
+// Run performs trials and records checkpoints.
+func (t Tracer) Run(e Experiment) Trace {
+	confidence := t.Confidence
+	if confidence == 0 {
+		confidence = 0.95
+	}
+	every := t.Every
+	if every <= 0 {
+		every = 1
+	}
+
+	trace := Trace{
+		Seed:    t.Seed,
+		Outcome: t.Outcome,
+	}
+
+	trials, successes := 0, 0
+	checkpoint := func() {
+		estimate := NewEstimate(successes, trials)
+		trace.Checkpoints = append(trace.Checkpoints, Checkpoint{
+			Trials:   trials,
+			Estimate: estimate,
+			Interval: estimate.Interval(t.Method, confidence),
+		})
+	}
+
+	for stream := 0; trials < t.Trials; stream++ {
+		rnd := rand.New(rand.NewSource(StreamSeed(t.Seed, stream)))
+		for i := 0; i < trialsPerStream && trials < t.Trials; i++ {
+			if e.Trial(rnd) == t.Outcome {
+				successes++
+			}
+			trials++
+
+			if trials%every == 0 {
+				checkpoint()
+			}
+		}
+	}
+
+	if trials%every != 0 {
+		checkpoint()
+	}
+
+	return trace
+}
+
+// BatchMeans is a diagnostic that splits trials into consecutive batches,
+// and compares how proportions in batches vary, with variation expected from independent trials.
+type BatchMeans struct {
+	// Means are proportions of the outcome in each batch
+	Means []float64
+	// Mean is average of batch means
+	Mean float64
+	// StdErr is batch means standard error sd(means)/√batches
+	StdErr float64
+	// Ratio of batch means standard error to standard error that assumes independent trials.
+	// Value close to 1 is expected, value much greater than 1 means
+	// that trials are correlated, or estimate has not converged yet.
+	Ratio float64
+}
+
+// BatchMeans computes diagnostic using given number of batches.
+// Batches are built from checkpoints, that's why number of batches
+// can't be greater than number of checkpoints, and trace should be recorded
+// with Every that divides number of trials.
+func (t Trace) BatchMeans(batches int) BatchMeans {
+	if batches > len(t.Checkpoints) {
+		batches = len(t.Checkpoints)
+	}
+	if batches < 2 {
+		return BatchMeans{Ratio: math.NaN(), StdErr: math.NaN()}
+	}
+
+	result := BatchMeans{
+		Means: make([]float64, batches),
+	}
+
+	perBatch := len(t.Checkpoints) / batches
+	prev := Checkpoint{}
+	for b := 0; b < batches; b++ {
+		cp := t.Checkpoints[(b+1)*perBatch-1]
+		trials := cp.Trials - prev.Trials
+		successes := cp.Estimate.Successes - prev.Estimate.Successes
+		result.Means[b] = float64(successes) / float64(trials)
+		result.Mean += result.Means[b] / float64(batches)
+		prev = cp
+	}
+
+	variance := .0
+	for _, m := range result.Means {
+		variance += (m - result.Mean) * (m - result.Mean)
+	}
+	variance /= float64(batches - 1)
+
+	result.StdErr = math.Sqrt(variance / float64(batches))
+	iid := NewEstimate(prev.Estimate.Successes, prev.Trials).StdErr
+	result.Ratio = result.StdErr / iid
+
+	return result
+}
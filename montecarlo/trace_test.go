@@ -0,0 +1,56 @@
+package montecarlo
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func TestTracer_Run(t *testing.T) {
+	tracer := Tracer{
+		Runner:  Runner{Trials: 10500, Seed: 5},
+		Outcome: Success,
+		Every:   1000,
+	}
+
+	trace := tracer.Run(coin)
+
+	assert.Len(t, trace.Checkpoints, 11)
+	assert.Equal(t, 1000, trace.Checkpoints[0].Trials)
+	assert.Equal(t, 10500, trace.Final().Trials)
+	assert.True(t, trace.Final().Interval.Contains(trace.Final().Estimate.Value))
+
+	// the same trials as in parallel runner, so the same estimate
+	expected := tracer.Runner.Run(coin).Estimate(Success)
+	assert.Equal(t, expected, trace.Final().Estimate)
+}
+
+func TestTrace_BatchMeans(t *testing.T) {
+	trace := Tracer{
+		Runner:  Runner{Trials: 100000, Seed: 5},
+		Outcome: Success,
+		Every:   1000,
+	}.Run(coin)
+
+	diagnostic := trace.BatchMeans(20)
+	assert.Len(t, diagnostic.Means, 20)
+	assert.InDelta(t, trace.Final().Estimate.Value, diagnostic.Mean, 1e-9)
+	assert.InDelta(t, 1, diagnostic.Ratio, 0.5)
+}
+
+func TestTrace_BatchMeans_notConverged(t *testing.T) {
+	// experiment which outcome drifts over time
+	trials := 0
+	drifting := ExperimentFunc(func(rnd *rand.Rand) Outcome {
+		trials++
+		return Binary(rnd.Float64() < float64(trials)/100000)
+	})
+
+	trace := Tracer{
+		Runner:  Runner{Trials: 100000},
+		Outcome: Success,
+		Every:   1000,
+	}.Run(drifting)
+
+	assert.Greater(t, trace.BatchMeans(20).Ratio, 5.0)
+}
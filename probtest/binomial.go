@@ -0,0 +1,35 @@
+package probtest
+
+import (
+	"github.com/widmogrod/probability-playground/distributions"
+	"math"
+)
+
+// BinomialTest computes p-value of exact two-sided binomial test.
+//
+// Null hypothesis says that successes were observed in n independent trials,
+// each with probability of success p. P-value is probability of observing
+// result that is at most as likely as observed one, when null hypothesis is true.
+// Small p-value means that observed proportion is unlikely under expected probability.
+//
+// Probabilities are computed in logarithmic space, so large n does not overflow.
+func BinomialTest(successes, n int, p float64) float64 {
+	if n <= 0 {
+		return 1
+	}
+
+	b := distributions.Binomial{N: n, P: p}
+	observed := b.LogPMF(successes)
+	// relative tolerance protects from rounding errors,
+	// when outcomes have the same probability in theory
+	threshold := observed + math.Log1p(1e-7)
+
+	pValue := .0
+	for k := 0; k <= n; k++ {
+		if lp := b.LogPMF(k); lp <= threshold {
+			pValue += math.Exp(lp)
+		}
+	}
+
+	return math.Min(1, pValue)
+}
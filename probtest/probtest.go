@@ -0,0 +1,112 @@
+// Package probtest contains assertions for tests of probabilistic code, like Monte Carlo simulations.
+//
+// Result of a simulation is random, that's why it can't be compared with exact value.
+// Instead of hand-picked bounds, assertions use statistical tests,
+// and fail only when result is unlikely under expected probability.
+// Probability of false alarm is controlled by alpha,
+// which is chance that correct simulation fails the test.
+package probtest
+
+import "math"
+
+// DefaultFalseAlarmRate is probability with which correct simulation can fail a test.
+// One failure in a million runs is rare enough to not bother anybody.
+const DefaultFalseAlarmRate = 1e-6
+
+// TestingT is the subset of testing.TB used by assertions.
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+type helper interface {
+	Helper()
+}
+
+// AssertProportion asserts that successes observed in n trials
+// are consistent with expected probability of success expectedP.
+//
+// Exact two-sided binomial test is used, and assertion fails
+// when p-value of the test is lower than alpha.
+func AssertProportion(t TestingT, successes, n int, expectedP, alpha float64) bool {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
+	pValue := BinomialTest(successes, n, expectedP)
+	if pValue < alpha {
+		t.Errorf("proportion not consistent with expected probability:\n"+
+			"\t observed: %d/%d = %f\n"+
+			"\t expected: %f\n"+
+			"\t binomial test p-value %g < alpha %g", successes, n, float64(successes)/float64(n), expectedP, pValue, alpha)
+		return false
+	}
+
+	return true
+}
+
+// AssertClose asserts that actual value is close to expected one.
+// Values are close when difference between them is not greater than
+// relative tolerance times bigger of absolute values, or absolute tolerance
+//
+//	|expected - actual| <= max(relTol * max(|expected|, |actual|), absTol)
+//
+// Absolute tolerance is needed when expected value is close to zero,
+// where relative tolerance becomes useless.
+func AssertClose(t TestingT, expected, actual, relTol, absTol float64) bool {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
+	diff := math.Abs(expected - actual)
+	tolerance := math.Max(relTol*math.Max(math.Abs(expected), math.Abs(actual)), absTol)
+	if !(diff <= tolerance) {
+		t.Errorf("values not close:\n"+
+			"\t expected: %g\n"+
+			"\t actual:   %g\n"+
+			"\t |diff| %g > tolerance %g", expected, actual, diff, tolerance)
+		return false
+	}
+
+	return true
+}
+
+// Suite groups many probabilistic assertions in one test,
+// and keeps probability that any of them raises false alarm under FalseAlarmRate.
+//
+// Each of checks is tested with alpha = FalseAlarmRate / checks (Bonferroni correction),
+// which guarantees that false alarm rate of whole test is not greater than requested one,
+// no matter if checks are independent or not.
+type Suite struct {
+	t TestingT
+	// FalseAlarmRate is probability that correct simulation fails any of checks
+	FalseAlarmRate float64
+	// Checks is number of assertions that will be made in the suite
+	Checks int
+}
+
+// NewSuite creates suite for given number of checks, that fails falsely with probability falseAlarmRate.
+func NewSuite(t TestingT, falseAlarmRate float64, checks int) *Suite {
+	if checks < 1 {
+		checks = 1
+	}
+
+	return &Suite{
+		t:              t,
+		FalseAlarmRate: falseAlarmRate,
+		Checks:         checks,
+	}
+}
+
+// Alpha is significance level of a single check in the suite.
+func (s *Suite) Alpha() float64 {
+	return s.FalseAlarmRate / float64(s.Checks)
+}
+
+// AssertProportion works like AssertProportion with alpha of the suite.
+func (s *Suite) AssertProportion(successes, n int, expectedP float64) bool {
+	if h, ok := s.t.(helper); ok {
+		h.Helper()
+	}
+
+	return AssertProportion(s.t, successes, n, expectedP, s.Alpha())
+}
@@ -0,0 +1,82 @@
+package probtest
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestBinomialTest(t *testing.T) {
+	useCases := map[string]struct {
+		successes, n int
+		p            float64
+		pValue       float64
+	}{
+		"fair coin 5/10": {
+			successes: 5, n: 10, p: 0.5,
+			pValue: 1,
+		},
+		"fair coin 9/10": {
+			successes: 9, n: 10, p: 0.5,
+			pValue: 0.021484375,
+		},
+		"die 0/30": {
+			successes: 0, n: 30, p: 1.0 / 6,
+			pValue: 0.006245,
+		},
+		"impossible success": {
+			successes: 1, n: 10, p: 0,
+			pValue: 0,
+		},
+		"large n": {
+			successes: 50000, n: 100000, p: 0.5,
+			pValue: 1,
+		},
+	}
+	for name, uc := range useCases {
+		t.Run(name, func(t *testing.T) {
+			assert.InDelta(t, uc.pValue, BinomialTest(uc.successes, uc.n, uc.p), 0.000001)
+		})
+	}
+}
+
+func TestAssertProportion(t *testing.T) {
+	r := &recorder{}
+
+	assert.True(t, AssertProportion(r, 5, 10, 0.5, 0.05))
+	assert.Len(t, r.errors, 0)
+
+	assert.False(t, AssertProportion(r, 9, 10, 0.5, 0.05))
+	assert.Len(t, r.errors, 1)
+}
+
+func TestAssertClose(t *testing.T) {
+	r := &recorder{}
+
+	assert.True(t, AssertClose(r, 100, 101, 0.01, 0))
+	assert.True(t, AssertClose(r, 0, 0.0001, 0.01, 0.001))
+	assert.Len(t, r.errors, 0)
+
+	assert.False(t, AssertClose(r, 100, 102, 0.01, 0))
+	assert.False(t, AssertClose(r, 0, 0.0001, 0.01, 0))
+	assert.Len(t, r.errors, 2)
+}
+
+func TestSuite(t *testing.T) {
+	r := &recorder{}
+	s := NewSuite(r, 0.01, 4)
+
+	assert.Equal(t, 0.0025, s.Alpha())
+	// p-value of 9/10 is ~0.021, significant alone but not after correction
+	assert.True(t, s.AssertProportion(9, 10, 0.5))
+	assert.False(t, s.AssertProportion(10, 10, 0.5))
+	assert.Len(t, r.errors, 1)
+}